@@ -0,0 +1,144 @@
+package golog
+
+import (
+	"context"
+	"sync"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// DefaultBufferSize is the max entries a buffered logger hold, the oldest is dropped when full
+const DefaultBufferSize = 1000
+
+// NewBuffered return a child of l hold the entries below threshold in memory, they are written
+// only when an entry at or above threshold come, then the later entries are written directly,
+// call done at the end of the unit of work to discard the entries never written
+func NewBuffered(l LoggerInterface, threshold Level) (child LoggerInterface, done func()) {
+	p, ok := l.(*logger)
+	if !ok {
+		return l, func() {}
+	}
+
+	buf := &entryBuffer{threshold: threshold, size: DefaultBufferSize}
+	c := p.child("", nil, p.directSkip())
+	c.buffer = buf
+	return c, buf.done
+}
+
+// NewBufferedContext same as NewBuffered for the logger in ctx, return ctx carry the child
+func NewBufferedContext(ctx context.Context, threshold Level) (context.Context, func()) {
+	child, done := NewBuffered(FromContext(ctx), threshold)
+	return NewContext(ctx, child), done
+}
+
+type bufferedEntry struct {
+	core   zapcore.Core
+	ent    zapcore.Entry
+	fields []zapcore.Field
+}
+
+// entryBuffer is shared by the buffered logger and its children
+type entryBuffer struct {
+	threshold Level
+	size      int
+
+	mu        sync.Mutex
+	entries   []bufferedEntry
+	triggered bool
+	closed    bool
+}
+
+// pass report whether the entry write directly, and whether it ignore the level,
+// only the entries below threshold after triggered ignore the level, flush the entries when it trigger
+func (b *entryBuffer) pass(lvl Level) (pass, force bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return true, false
+	}
+
+	if b.triggered {
+		return true, lvl < b.threshold
+	}
+
+	if lvl < b.threshold {
+		return false, false
+	}
+
+	b.triggered = true
+	for _, e := range b.entries {
+		if ce := e.core.Check(e.ent, nil); ce != nil {
+			ce.Write(e.fields...)
+		}
+	}
+	b.entries = nil
+	return true, false
+}
+
+func (b *entryBuffer) add(e bufferedEntry) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	// closed between Check and Write, discard it as the others
+	if b.closed {
+		return
+	}
+
+	// triggered between Check and Write
+	if b.triggered {
+		if ce := e.core.Check(e.ent, nil); ce != nil {
+			ce.Write(e.fields...)
+		}
+		return
+	}
+
+	if len(b.entries) >= b.size {
+		b.entries = b.entries[1:]
+	}
+	b.entries = append(b.entries, e)
+}
+
+func (b *entryBuffer) done() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	b.entries = nil
+}
+
+func (b *entryBuffer) wrap(core zapcore.Core) zapcore.Core {
+	return &bufferCore{Core: core, buf: b}
+}
+
+// bufferCore take all the entries, the ones below threshold go to the buffer
+type bufferCore struct {
+	zapcore.Core
+	buf *entryBuffer
+}
+
+// Enabled all the levels can be buffered
+func (c *bufferCore) Enabled(zapcore.Level) bool {
+	return true
+}
+
+func (c *bufferCore) With(fields []zapcore.Field) zapcore.Core {
+	return &bufferCore{Core: c.Core.With(fields), buf: c.buf}
+}
+
+func (c *bufferCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if pass, force := c.buf.pass(ent.Level); pass {
+		if force {
+			// write the entries after triggered ignoring the level, same as the buffered ones
+			return forceCore(c.Core).Check(ent, ce)
+		}
+		return c.Core.Check(ent, ce)
+	}
+	return ce.AddCore(ent, c)
+}
+
+// Write only called for the entries to buffer, they ignore the level when written
+func (c *bufferCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	c.buf.add(bufferedEntry{core: forceCore(c.Core), ent: ent, fields: fields})
+	return nil
+}
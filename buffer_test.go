@@ -0,0 +1,74 @@
+package golog
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBuffered(t *testing.T) {
+	dir := t.TempDir()
+	l := New().SetOutputFile(dir, "buf").SetOutputSingleFile(true).SetOutputJson(true).SetCallerShort(true)
+	l.InitLogger()
+
+	ok, done := NewBuffered(l, WarnLevel)
+	ok.Debug("ok debug")
+	ok.Info("ok info")
+	done()
+
+	// after done the level take effect again
+	ok.Debug("done debug")
+	ok.Info("done info")
+
+	// forced entries skip the buffer, no warn needed and written after done
+	ctx, done := NewBufferedContext(ForceDebug(NewContext(context.Background(), l)), WarnLevel)
+	DebugContext(ctx, "forced debug")
+	done()
+	DebugContext(ctx, "forced after done")
+
+	ctx, done = NewBufferedContext(NewContext(context.Background(), l), WarnLevel)
+	failedDebug := nextCaller()
+	DebugContext(ctx, "failed debug")
+	failedInfo := nextCaller()
+	FromContext(ctx).Named("sub").Info("failed info")
+	failedWarn := nextCaller()
+	WarnContext(ctx, "failed warn")
+	failedAfter := nextCaller()
+	InfoContext(ctx, "failed after")
+	done()
+
+	b, err := ioutil.ReadFile(filepath.Join(dir, "buf.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := string(b)
+	if strings.Contains(out, "ok ") {
+		t.Errorf("the buffer without warn should be discarded: %s", out)
+	}
+
+	if strings.Contains(out, "done debug") || !strings.Contains(out, "done info") {
+		t.Errorf("the entries after done should follow the level: %s", out)
+	}
+
+	if !strings.Contains(out, "forced debug") || !strings.Contains(out, "forced after done") {
+		t.Errorf("the forced entries should be written: %s", out)
+	}
+
+	last := -1
+	for _, want := range []string{
+		failedDebug + `,"msg":"failed debug"`,
+		`"logger":"sub","caller":"`,
+		failedInfo + `,"msg":"failed info"`,
+		failedWarn + `,"msg":"failed warn"`,
+		failedAfter + `,"msg":"failed after"`,
+	} {
+		i := strings.Index(out, want)
+		if i <= last {
+			t.Errorf("%s should be in %s in order", want, out)
+		}
+		last = i
+	}
+}
@@ -0,0 +1,116 @@
+package golog
+
+import (
+	"go.uber.org/zap"
+)
+
+// With return a child logger with the fields bound, it shares the cores and level with the parent,
+// so create it once per request and pass it down is cheap, config methods of the child act on the parent
+func With(fields map[string]interface{}) LoggerInterface {
+	// the child is called directly, not through the package funcs
+	return _log.(*logger).child("", with(fields), -1)
+}
+
+func (l *logger) With(fields map[string]interface{}) LoggerInterface {
+	return l.child("", with(fields), l.directSkip())
+}
+
+// Named return a child logger named parent.sub, the level override of the name take effect on it
+func Named(sub string) LoggerInterface {
+	return _log.(*logger).child(sub, nil, -1)
+}
+
+func (l *logger) Named(sub string) LoggerInterface {
+	return l.child(sub, nil, l.directSkip())
+}
+
+// WithCallerSkip return a child logger skip more callers, used by the adapter wrap the logger in more layers
+func WithCallerSkip(skip int) LoggerInterface {
+	return _log.(*logger).child("", nil, -1+skip)
+}
+
+func (l *logger) WithCallerSkip(skip int) LoggerInterface {
+	return l.child("", nil, l.directSkip()+skip)
+}
+
+// Enabled report whether level can be written by the default logger
+func Enabled(level Level) bool {
+	return _log.Enabled(level)
+}
+
+// Enabled report whether level can be written by this logger, the level override of its name considered
+func (l *logger) Enabled(level Level) bool {
+	return l.enabled(level)
+}
+
+// directSkip the skip delta of a child called directly, the default logger skip one more layer
+// for the package funcs, but its children are never called by them
+func (l *logger) directSkip() int {
+	if l == _log {
+		return -1
+	}
+	return 0
+}
+
+func (l *logger) child(sub string, fields []interface{}, skipDelta int) *logger {
+	c := &logger{shared: l.shared, named: l.named, skipDelta: l.skipDelta + skipDelta, buffer: l.buffer}
+	if sub != "" {
+		if c.named == "" {
+			c.named = sub
+		} else {
+			c.named = c.named + "." + sub
+		}
+	}
+
+	c.fields = make([]interface{}, 0, len(l.fields)+len(fields))
+	c.fields = append(c.fields, l.fields...)
+	c.fields = append(c.fields, fields...)
+	return c
+}
+
+// current return the zap logger in use, must hold the read lock of coreMu
+func (l *logger) current() (*zap.Logger, *zap.SugaredLogger) {
+	if l.named == "" && len(l.fields) == 0 && l.skipDelta == 0 && l.buffer == nil {
+		return l.zapLogger, l.sugarLog
+	}
+
+	if d, ok := l.derived.Load().(*derived); ok && d.gen == l.gen {
+		return d.zapLogger, d.sugarLog
+	}
+
+	z := l.zapLogger
+	if l.skipDelta != 0 {
+		z = z.WithOptions(zap.AddCallerSkip(l.skipDelta))
+	}
+
+	if l.buffer != nil {
+		z = z.WithOptions(zap.WrapCore(l.buffer.wrap))
+	}
+
+	if l.named != "" {
+		z = z.Named(l.named)
+	}
+
+	s := z.Sugar()
+	if len(l.fields) > 0 {
+		s = s.With(l.fields...)
+		z = s.Desugar()
+	}
+
+	l.derived.Store(&derived{gen: l.gen, zapLogger: z, sugarLog: s})
+	return z, s
+}
+
+// enabled report whether lvl can be written by this logger, the level override of its name considered
+func (l *logger) enabled(lvl Level) bool {
+	name := l.GetName()
+
+	l.coreMu.RLock()
+	defer l.coreMu.RUnlock()
+
+	z, _ := l.current()
+	if c, ok := z.Core().(*levelCore); ok {
+		return c.of(name).Enabled(lvl)
+	}
+	return z.Core().Enabled(lvl)
+}
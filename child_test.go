@@ -0,0 +1,47 @@
+package golog
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWithNamed(t *testing.T) {
+	dir := t.TempDir()
+	l := New().SetName("svc").SetOutputFile(dir, "child").SetOutputJson(true).SetCallerShort(true)
+	l.InitLogger()
+
+	c := l.With(map[string]interface{}{"request_id": "r1"}).Named("billing")
+	if c.GetName() != "svc.billing" {
+		t.Fatalf("unexpected name: %s", c.GetName())
+	}
+
+	c.Named("sql").InfoWithFields(map[string]interface{}{"table": "t"}, "query %d", 1)
+
+	// the child follow the parent when reinit
+	l.SetOutputFile(dir, "child2")
+	l.InitLogger()
+	c.Info("after reinit")
+
+	b, err := ioutil.ReadFile(filepath.Join(dir, "child_info.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := string(b)
+	for _, want := range []string{`"logger":"svc.billing.sql"`, `"request_id":"r1"`, `"table":"t"`, `"msg":"query 1"`, `child_test.go`} {
+		if !strings.Contains(out, want) {
+			t.Errorf("%s should be in %s", want, out)
+		}
+	}
+
+	b, err = ioutil.ReadFile(filepath.Join(dir, "child2_info.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(string(b), "after reinit") {
+		t.Errorf("child should write the new cores: %s", b)
+	}
+}
@@ -0,0 +1,38 @@
+package golog
+
+import (
+	"os"
+
+	"github.com/mattn/go-isatty"
+)
+
+const (
+	// ColorAuto color the stdout only when it is a terminal, NO_COLOR and FORCE_COLOR are respected
+	ColorAuto = ""
+	// ColorAlways always color the stdout
+	ColorAlways = "always"
+	// ColorNever never color
+	ColorNever = "never"
+)
+
+// colored report whether the console encoder of stdout write the color codes, files never colored
+func (c config) colored() bool {
+	switch c.color {
+	case ColorAlways:
+		return true
+	case ColorNever:
+		return false
+	}
+
+	// https://no-color.org
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+
+	if force := os.Getenv("FORCE_COLOR"); force != "" && force != "0" && force != "false" {
+		return true
+	}
+
+	fd := os.Stdout.Fd()
+	return isatty.IsTerminal(fd) || isatty.IsCygwinTerminal(fd)
+}
@@ -0,0 +1,59 @@
+package golog
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestOutputColor(t *testing.T) {
+	dir := t.TempDir()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stdout := os.Stdout
+	os.Stdout = w
+	l := New().SetOutputFile(dir, "color").SetIsOutputStdout(true).SetOutputColor(ColorAlways)
+	l.InitLogger()
+	os.Stdout = stdout
+
+	l.Info("colored")
+	w.Close()
+
+	out, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(string(out), "\x1b[34mINFO\x1b[0m") {
+		t.Errorf("stdout should be colored: %q", out)
+	}
+
+	b, err := os.ReadFile(filepath.Join(dir, "color_info.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Contains(string(b), "\x1b[") || !strings.Contains(string(b), "\tINFO\t") {
+		t.Errorf("file should not be colored: %q", b)
+	}
+
+	t.Setenv("NO_COLOR", "1")
+	t.Setenv("FORCE_COLOR", "1")
+	if (config{}).colored() {
+		t.Error("NO_COLOR should disable color")
+	}
+
+	t.Setenv("NO_COLOR", "")
+	if !(config{}).colored() {
+		t.Error("FORCE_COLOR should enable color")
+	}
+
+	if (config{color: ColorNever}).colored() {
+		t.Error("ColorNever should override FORCE_COLOR")
+	}
+}
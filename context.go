@@ -0,0 +1,78 @@
+package golog
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+type loggerKey struct{}
+
+// ctxLogger is the logger carried in context
+type ctxLogger struct {
+	l LoggerInterface
+	// pkg is l with one more caller skip, used by the package funcs like InfoContext
+	pkg atomic.Value
+}
+
+// NewContext return a copy of ctx carry l, usually a child by With or Named,
+// the package funcs like InfoContext log by it
+func NewContext(ctx context.Context, l LoggerInterface) context.Context {
+	return context.WithValue(ctx, loggerKey{}, &ctxLogger{l: l})
+}
+
+// directLog is the default logger called directly, the caller skip of the default logger is for the package funcs
+var directLog LoggerInterface = _log.(*logger).child("", nil, -1)
+
+// FromContext return the logger carried by ctx, or the default logger when none
+func FromContext(ctx context.Context) LoggerInterface {
+	if ctx != nil {
+		if c, ok := ctx.Value(loggerKey{}).(*ctxLogger); ok && c.l != _log {
+			return c.l
+		}
+	}
+	return directLog
+}
+
+// ctxPkgLogger like FromContext but used by the package funcs, they wrap one more layer,
+// the default logger already skip it, all the other loggers include its children are called directly
+func ctxPkgLogger(ctx context.Context) LoggerInterface {
+	if ctx == nil {
+		return _log
+	}
+
+	c, ok := ctx.Value(loggerKey{}).(*ctxLogger)
+	if !ok || c.l == _log {
+		return _log
+	}
+
+	l, ok := c.l.(*logger)
+	if !ok {
+		return c.l
+	}
+
+	if pkg, ok := c.pkg.Load().(LoggerInterface); ok {
+		return pkg
+	}
+
+	pkg := l.child("", nil, 1)
+	c.pkg.Store(LoggerInterface(pkg))
+	return pkg
+}
+
+type forceDebugKey struct{}
+
+// ForceDebug return a copy of ctx marked as "debug this request",
+// DebugContext and DebugContextWithFields write even the level is above debug
+func ForceDebug(ctx context.Context) context.Context {
+	return context.WithValue(ctx, forceDebugKey{}, true)
+}
+
+// IsForceDebug report whether ctx is marked by ForceDebug
+func IsForceDebug(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+
+	force, _ := ctx.Value(forceDebugKey{}).(bool)
+	return force
+}
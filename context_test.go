@@ -0,0 +1,150 @@
+package golog
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// nextCaller return the json caller and func of the line after the call, the logged caller should be it
+func nextCaller() string {
+	pc, file, line, _ := runtime.Caller(1)
+	return fmt.Sprintf(`%s:%d","func":"%s"`, filepath.Base(file), line+1, runtime.FuncForPC(pc).Name())
+}
+
+func TestContextLogger(t *testing.T) {
+	if FromContext(context.Background()) != directLog {
+		t.Fatal("want the default logger when none in context")
+	}
+
+	dir := t.TempDir()
+	l := New().SetOutputFile(dir, "ctx").SetOutputJson(true).SetCallerShort(true)
+	l.InitLogger()
+
+	ctx := NewContext(context.Background(), l.With(map[string]interface{}{"user": "u1"}))
+	byPkg := nextCaller()
+	InfoContext(ctx, "by package func")
+	byMethod := nextCaller()
+	FromContext(ctx).Info("by method")
+
+	h := HTTPMiddleware(l)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		FromContext(r.Context()).Info("in handler")
+	}))
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Request-Id", "rid")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	b, err := ioutil.ReadFile(filepath.Join(dir, "ctx_info.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := string(b)
+	for _, want := range []string{
+		byPkg + `,"msg":"by package func","user":"u1"`,
+		byMethod + `,"msg":"by method","user":"u1"`,
+		`"msg":"in handler","http.request_id":"rid"`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("%s should be in %s", want, out)
+		}
+	}
+}
+
+func TestForceDebug(t *testing.T) {
+	dir := t.TempDir()
+	l := New().SetOutputFile(dir, "force").SetCallerShort(true)
+	l.InitLogger()
+
+	h := HTTPMiddleware(l, WithDebugHeader("X-Debug"))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		l.DebugContext(r.Context(), "debug %s", r.URL.Path)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/forced", nil)
+	req.Header.Set("X-Debug", "true")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/normal", nil))
+	l.DebugContextWithFields(ForceDebug(context.Background()), map[string]interface{}{"k": "v"}, "with fields")
+
+	if l.GetLevel() != InfoLevel {
+		t.Fatal("level should not change")
+	}
+
+	b, err := ioutil.ReadFile(filepath.Join(dir, "force_debug.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := string(b)
+	if !strings.Contains(out, "debug /forced") || !strings.Contains(out, "with fields") || !strings.Contains(out, "context_test.go") {
+		t.Errorf("forced debug should be written: %s", out)
+	}
+
+	if strings.Contains(out, "debug /normal") {
+		t.Errorf("debug should not be written without force: %s", out)
+	}
+
+	b, err = ioutil.ReadFile(filepath.Join(dir, "force_info.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Contains(string(b), "debug /forced") {
+		t.Errorf("forced debug should not go to the info file: %s", b)
+	}
+}
+
+func TestContextDefaultLogger(t *testing.T) {
+	logPath, fileName := GetOutputFile()
+	json, short := GetOutputJson(), GetCallerShort()
+	t.Cleanup(func() {
+		SetOutputFile(logPath, fileName).SetOutputJson(json).SetCallerShort(short)
+		InitLogger()
+	})
+
+	dir := t.TempDir()
+	SetOutputFile(dir, "def").SetOutputJson(true).SetCallerShort(true)
+	InitLogger()
+
+	var byMethod, byPkg string
+	h := HTTPMiddleware(Logger())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		byMethod = nextCaller()
+		FromContext(r.Context()).Info("by method")
+		byPkg = nextCaller()
+		InfoContext(r.Context(), "by package func")
+	}))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	byNamed := nextCaller()
+	Logger().Named("sub").Info("by named")
+	byWith := nextCaller()
+	Logger().With(map[string]interface{}{"k": "v"}).Info("by with")
+	byNoLogger := nextCaller()
+	FromContext(context.Background()).Info("by no logger")
+	byDefault := nextCaller()
+	FromContext(NewContext(context.Background(), Logger())).Info("by default in context")
+
+	b, err := ioutil.ReadFile(filepath.Join(dir, "def_info.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := string(b)
+	for _, want := range []string{
+		byMethod + `,"msg":"by method"`,
+		byPkg + `,"msg":"by package func"`,
+		byNamed + `,"msg":"by named"`,
+		byWith + `,"msg":"by with"`,
+		byNoLogger + `,"msg":"by no logger"`,
+		byDefault + `,"msg":"by default in context"`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("%s should be in %s", want, out)
+		}
+	}
+}
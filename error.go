@@ -0,0 +1,41 @@
+package golog
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ConfigProblem is one invalid config item
+type ConfigProblem struct {
+	// Field the config name, eg: logPath, level
+	Field string
+	// Value the invalid value
+	Value interface{}
+	Err   error
+}
+
+func (p ConfigProblem) Error() string {
+	return fmt.Sprintf("%s(%v): %s", p.Field, p.Value, p.Err)
+}
+
+func (p ConfigProblem) Unwrap() error {
+	return p.Err
+}
+
+// ConfigError collect all the problems found by InitLoggerE
+type ConfigError struct {
+	Problems []ConfigProblem
+}
+
+func (e *ConfigError) add(field string, value interface{}, err error) {
+	e.Problems = append(e.Problems, ConfigProblem{Field: field, Value: value, Err: err})
+}
+
+func (e *ConfigError) Error() string {
+	s := make([]string, 0, len(e.Problems))
+	for _, p := range e.Problems {
+		s = append(s, p.Error())
+	}
+
+	return "golog: invalid config: " + strings.Join(s, "; ")
+}
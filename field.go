@@ -0,0 +1,119 @@
+package golog
+
+import (
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Field is the strongly typed field, encode it need no reflection and keep the order
+type Field = zap.Field
+
+// ObjectMarshaler let the type encode itself as a field by Object
+type ObjectMarshaler = zapcore.ObjectMarshaler
+
+func String(key string, val string) Field {
+	return zap.String(key, val)
+}
+
+func Int(key string, val int) Field {
+	return zap.Int(key, val)
+}
+
+func Int64(key string, val int64) Field {
+	return zap.Int64(key, val)
+}
+
+func Uint64(key string, val uint64) Field {
+	return zap.Uint64(key, val)
+}
+
+func Float64(key string, val float64) Field {
+	return zap.Float64(key, val)
+}
+
+func Bool(key string, val bool) Field {
+	return zap.Bool(key, val)
+}
+
+func Duration(key string, val time.Duration) Field {
+	return zap.Duration(key, val)
+}
+
+func Time(key string, val time.Time) Field {
+	return zap.Time(key, val)
+}
+
+// Err is the field with key error, skip when err is nil
+func Err(err error) Field {
+	return zap.Error(err)
+}
+
+// Any choose the best way to encode val, fall back to reflection
+func Any(key string, val interface{}) Field {
+	return zap.Any(key, val)
+}
+
+func Object(key string, val ObjectMarshaler) Field {
+	return zap.Object(key, val)
+}
+
+// logF all the typed field methods come here, same as log but no sugar
+func (l *logger) logF(lvl Level, msg string, fields []Field) {
+	l.coreMu.RLock()
+	defer l.coreMu.RUnlock()
+
+	z, _ := l.current()
+	if ce := z.Check(lvl, msg); ce != nil {
+		ce.Write(fields...)
+	}
+}
+
+func (l *logger) PanicF(msg string, fields ...Field) {
+	l.logF(PanicLevel, msg, fields)
+}
+
+func PanicF(msg string, fields ...Field) {
+	_log.PanicF(msg, fields...)
+}
+
+func (l *logger) FatalF(msg string, fields ...Field) {
+	l.logF(FatalLevel, msg, fields)
+}
+
+func FatalF(msg string, fields ...Field) {
+	_log.FatalF(msg, fields...)
+}
+
+func (l *logger) ErrorF(msg string, fields ...Field) {
+	l.logF(ErrorLevel, msg, fields)
+}
+
+func ErrorF(msg string, fields ...Field) {
+	_log.ErrorF(msg, fields...)
+}
+
+func (l *logger) WarnF(msg string, fields ...Field) {
+	l.logF(WarnLevel, msg, fields)
+}
+
+func WarnF(msg string, fields ...Field) {
+	_log.WarnF(msg, fields...)
+}
+
+func (l *logger) InfoF(msg string, fields ...Field) {
+	l.logF(InfoLevel, msg, fields)
+}
+
+func InfoF(msg string, fields ...Field) {
+	_log.InfoF(msg, fields...)
+}
+
+func (l *logger) DebugF(msg string, fields ...Field) {
+	l.logF(DebugLevel, msg, fields)
+}
+
+func DebugF(msg string, fields ...Field) {
+	_log.DebugF(msg, fields...)
+}
@@ -1,9 +1,11 @@
 module github.com/hunterhug/golog
 
-go 1.17
+go 1.21
 
 require (
 	github.com/lestrrat-go/file-rotatelogs v2.4.0+incompatible
+	github.com/mattn/go-isatty v0.0.24
+	go.uber.org/multierr v1.7.0
 	go.uber.org/zap v1.19.0
 )
 
@@ -12,5 +14,5 @@ require (
 	github.com/lestrrat-go/strftime v1.0.5 // indirect
 	github.com/pkg/errors v0.9.1 // indirect
 	go.uber.org/atomic v1.9.0 // indirect
-	go.uber.org/multierr v1.7.0 // indirect
+	golang.org/x/sys v0.28.0 // indirect
 )
@@ -2,32 +2,99 @@ package golog
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	rotateLogs "github.com/lestrrat-go/file-rotatelogs"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 	"io"
+	"io/ioutil"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"sort"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
-type logger struct {
-	name         string
-	zapLogger    *zap.Logger
-	sugarLog     *zap.SugaredLogger
-	level        Level
-	short        bool
-	json         bool
-	addFieldFunc func(context.Context, map[string]interface{})
+// config is everything can be set before InitLogger
+type config struct {
+	name string
+	// level is shared by all the cores, change it take effect at once
+	level zap.AtomicLevel
+	// levels is the level of the named logger, it overrides level
+	levels *levelTable
+	short  bool
+	json   bool
 
 	logPath  string
 	fileName string
 
 	fileMaxAge, fileRotation time.Duration
+	// fileMaxSize rotate the file when it is larger, together with fileRotation whichever first
+	fileMaxSize int64
+	// fileMaxBackups keep at most the number of rotated files for each file
+	fileMaxBackups int
+	// logPathMaxSize the total size of the files in log path, the oldest rotated files removed when exceeded
+	logPathMaxSize int64
+	// fileCompress compress the rotated files by CompressGzip or the registered one
+	fileCompress string
+	// outputs replace the default four files under the log path when set
+	outputs []Output
+	// singleFile write all the entries to one file instead of the default four files
+	singleFile bool
+	// color the level of stdout, ColorAuto, ColorAlways or ColorNever
+	color string
+	// stdoutEncoder and fileEncoder EncoderJSON or EncoderConsole, empty follow json
+	stdoutEncoder, fileEncoder string
 
 	isOutputStdout bool
 	skip           int
+	// accessLog write the logger named access to access.log instead of the level files
+	accessLog bool
+
+	// slogHandler replace all the outputs when set, see NewFromSlog
+	slogHandler slog.Handler
+}
+
+// shared is everything the logger and its children created by With and Named share
+type shared struct {
+	// mu guard the config and fieldFuncs
+	mu sync.RWMutex
+	config
+	// fieldFuncs run by order, never modified in place so can be read without lock after loaded
+	fieldFuncs []namedFieldFunc
+
+	// initMu make InitLogger run one by one
+	initMu sync.Mutex
+
+	// coreMu guard the cores in use, logging hold the read lock,
+	// InitLogger hold the write lock to swap in the new cores
+	coreMu    sync.RWMutex
+	zapLogger *zap.Logger
+	sugarLog  *zap.SugaredLogger
+	closers   []io.Closer
+	// gen increase when the cores swapped, children rebuild their zap logger when it changes
+	gen uint64
+}
+
+type logger struct {
+	*shared
+
+	// below only used by the children
+	named     string
+	fields    []interface{}
+	skipDelta int
+	buffer    *entryBuffer
+	derived   atomic.Value
+}
+
+// derived is the zap logger of a child, built from the shared zap logger of gen
+type derived struct {
+	gen       uint64
+	zapLogger *zap.Logger
+	sugarLog  *zap.SugaredLogger
 }
 
 var _log = New()
@@ -46,15 +113,68 @@ func Logger() LoggerInterface {
 
 // New can new a logger interface, you can config it by it's method
 func New() LoggerInterface {
-	l := new(logger)
-	l.level = InfoLevel
+	l := &logger{shared: new(shared)}
+	l.level = zap.NewAtomicLevelAt(InfoLevel)
+	l.levels = newLevelTable()
 	l.short = false
 	l.json = false
+
+	if spec := os.Getenv(LevelSpecEnv); spec != "" {
+		if err := l.SetLevelSpec(spec); err != nil {
+			fmt.Fprintf(os.Stderr, "golog: ignore %s: %s\n", LevelSpecEnv, err)
+		}
+	}
 	return l
 }
 
-// InitLogger after config you must call this method
+// InitLogger after config you must call this method, it panic when config is invalid
 func (l *logger) InitLogger() {
+	if err := l.InitLoggerE(); err != nil {
+		panic(err)
+	}
+}
+
+// InitLoggerE same as InitLogger but return the error instead of panic,
+// when config is invalid the previous logger keep working
+func (l *logger) InitLoggerE() error {
+	l.initMu.Lock()
+	defer l.initMu.Unlock()
+
+	l.mu.RLock()
+	c := l.config
+	l.mu.RUnlock()
+
+	if err := c.validate(); err != nil {
+		return err
+	}
+
+	zapLogger, closers, err := c.build()
+	if err != nil {
+		return err
+	}
+
+	l.coreMu.Lock()
+	oldLogger, oldClosers := l.zapLogger, l.closers
+	l.zapLogger = zapLogger
+	l.sugarLog = zapLogger.Sugar()
+	l.closers = closers
+	l.gen++
+	l.coreMu.Unlock()
+
+	// no one is writing the old cores now, flush and close them
+	if oldLogger != nil {
+		_ = oldLogger.Sync()
+	}
+
+	for _, closer := range oldClosers {
+		_ = closer.Close()
+	}
+
+	return nil
+}
+
+// build the zap logger and the file writers need to close when replaced
+func (c config) build() (*zap.Logger, []io.Closer, error) {
 	encoderConfig := zap.NewProductionEncoderConfig()
 	encoderConfig.LevelKey = "l"
 	encoderConfig.FunctionKey = "func"
@@ -63,146 +183,255 @@ func (l *logger) InitLogger() {
 	encoderConfig.MessageKey = "msg"
 	encoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
 
-	if l.short {
+	if c.short {
 		encoderConfig.EncodeCaller = zapcore.ShortCallerEncoder
 	} else {
 		encoderConfig.EncodeCaller = zapcore.FullCallerEncoder
 	}
 
 	encoderConfig.LineEnding = zapcore.DefaultLineEnding
-	var zConfig zapcore.Encoder
-
-	if !l.json {
-		encoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
-		zConfig = zapcore.NewConsoleEncoder(encoderConfig)
+	newEncoder := func(encoder string, color bool) zapcore.Encoder {
+		if encoder == EncoderJSON || (encoder == "" && c.json) {
+			return zapcore.NewJSONEncoder(encoderConfig)
+		}
 
-	} else {
-		zConfig = zapcore.NewJSONEncoder(encoderConfig)
+		consoleConfig := encoderConfig
+		consoleConfig.EncodeLevel = zapcore.CapitalLevelEncoder
+		if color {
+			consoleConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
+		}
+		return zapcore.NewConsoleEncoder(consoleConfig)
 	}
 
+	// only the stdout can be colored
+	fileEncoder := newEncoder(c.fileEncoder, false)
+	stdoutEncoder := newEncoder(c.stdoutEncoder, c.colored())
+
 	var outCore zapcore.Core
 
-	if l.logPath != "" {
+	var closers []io.Closer
+	if c.slogHandler != nil {
+		outCore = newSlogCore(c.slogHandler)
+	} else if c.logPath != "" {
 		cores := make([]zapcore.Core, 0)
-		debugFileName := filepath.Join(l.logPath, "access.log")
-		infoFileName := filepath.Join(l.logPath, "info.log")
-		warnFileName := filepath.Join(l.logPath, "warn.log")
-		errFileName := filepath.Join(l.logPath, "error.log")
-
-		if l.fileName != "" {
-			debugFileName = filepath.Join(l.logPath, l.fileName) + "_debug.log"
-			infoFileName = filepath.Join(l.logPath, l.fileName) + "_info.log"
-			warnFileName = filepath.Join(l.logPath, l.fileName) + "_warn.log"
-			errFileName = filepath.Join(l.logPath, l.fileName) + "_err.log"
+		accessFileName := filepath.Join(c.logPath, "access.log")
+		if c.fileName != "" {
+			accessFileName = filepath.Join(c.logPath, c.fileName) + "_access.log"
 		}
 
-		if l.level <= DebugLevel {
-			debugLevel := zap.LevelEnablerFunc(func(lvl zapcore.Level) bool {
-				return lvl >= DebugLevel
-			})
-
-			debugWriter := getWriter(false, debugFileName, l.fileMaxAge, l.fileRotation)
-			debugWriteSync := zapcore.AddSync(debugWriter)
-			core := zapcore.NewCore(
-				zConfig,
-				debugWriteSync,
-				debugLevel,
-			)
+		outputs := c.fileOutputs()
 
-			cores = append(cores, core)
+		// all the file writers share the retention, the log path is swept after any of them rotated
+		keep := &retention{
+			maxAge:     c.fileMaxAge,
+			maxBackups: c.fileMaxBackups,
+			maxSize:    c.logPathMaxSize,
+			compress:   c.fileCompress,
 		}
-
-		if l.level <= InfoLevel {
-			infoLevel := zap.LevelEnablerFunc(func(lvl zapcore.Level) bool {
-				return lvl >= InfoLevel
-			})
-
-			infoWriter := getWriter(false, infoFileName, l.fileMaxAge, l.fileRotation)
-			infoWriteSync := zapcore.AddSync(infoWriter)
-			core := zapcore.NewCore(
-				zConfig,
-				infoWriteSync,
-				infoLevel,
-			)
-			cores = append(cores, core)
+		newWriter := func(fileName string) (io.Writer, error) {
+			opts := []rotateLogs.Option{rotateLogs.WithHandler(keep)}
+			if c.fileMaxSize > 0 {
+				opts = append(opts, rotateLogs.WithRotationSize(c.fileMaxSize))
+			}
+
+			writer, err := getWriter(false, fileName, c.fileMaxAge, c.fileRotation, opts...)
+			if err != nil {
+				return nil, err
+			}
+
+			if file, ok := writer.(*fileWriter); ok {
+				keep.add(fileName, file)
+			}
+			return writer, nil
 		}
 
-		if l.level <= WarnLevel {
-			warnLevel := zap.LevelEnablerFunc(func(lvl zapcore.Level) bool {
-				return lvl >= WarnLevel
+		// all the file cores are built whatever the level is, levelCore and fileCore decide what can be written,
+		// so SetLevel can enable them later, the file is not created until the first write
+		problems := new(ConfigError)
+		names := newNameLevel(c)
+		for _, o := range outputs {
+			// validated already
+			minLevel, maxLevel, _ := o.levels()
+			enabler := zap.LevelEnablerFunc(func(lvl zapcore.Level) bool {
+				return lvl >= minLevel && lvl <= maxLevel
 			})
 
-			warnWriter := getWriter(false, warnFileName, l.fileMaxAge, l.fileRotation)
-			warnWriteSync := zapcore.AddSync(warnWriter)
-			core := zapcore.NewCore(
-				zConfig,
-				warnWriteSync,
-				warnLevel,
+			fileName := o.fileName(c.logPath, c.fileName)
+			writer, err := newWriter(fileName)
+			if err != nil {
+				problems.add("fileName", fileName, err)
+				continue
+			}
+
+			encoder := o.Encoder
+			if encoder == "" {
+				encoder = c.fileEncoder
+			}
+
+			var core zapcore.Core = zapcore.NewCore(
+				newEncoder(encoder, false),
+				zapcore.AddSync(writer),
+				enabler,
 			)
+			if o.leveled() {
+				core = &fileCore{Core: core, nameLevel: names, min: minLevel}
+			}
 			cores = append(cores, core)
+			if closer, ok := writer.(io.Closer); ok {
+				closers = append(closers, closer)
+			}
 		}
 
-		if l.level <= ErrorLevel {
-			errorLevel := zap.LevelEnablerFunc(func(lvl zapcore.Level) bool {
-				return lvl >= ErrorLevel
-			})
-
-			errorWriter := getWriter(false, errFileName, l.fileMaxAge, l.fileRotation)
-			errorWriteSync := zapcore.AddSync(errorWriter)
-			core := zapcore.NewCore(
-				zConfig,
-				errorWriteSync,
-				errorLevel,
-			)
-			cores = append(cores, core)
+		// the access logger only write the access file
+		var accessCores []zapcore.Core
+		if c.accessLog {
+			writer, err := newWriter(accessFileName)
+			if err != nil {
+				problems.add("fileName", accessFileName, err)
+			} else {
+				accessCores = append(accessCores, zapcore.NewCore(fileEncoder, zapcore.AddSync(writer), zapcore.DebugLevel))
+				if closer, ok := writer.(io.Closer); ok {
+					closers = append(closers, closer)
+				}
+			}
+		}
 
+		if len(problems.Problems) > 0 {
+			return nil, nil, problems
 		}
 
-		if l.isOutputStdout {
-			stdOutLevel := zap.LevelEnablerFunc(func(lvl zapcore.Level) bool {
-				return lvl >= l.level
-			})
+		if c.isOutputStdout {
 			core := zapcore.NewCore(
-				zConfig,
+				stdoutEncoder,
 				zapcore.AddSync(os.Stdout),
-				stdOutLevel,
+				zapcore.DebugLevel,
 			)
 			cores = append(cores, core)
+			accessCores = append(accessCores, core)
 		}
 		outCore = zapcore.NewTee(cores...)
 
+		if c.accessLog {
+			outCore = newAccessCore(outCore, zapcore.NewTee(accessCores...))
+		}
+
 	} else {
 		writeSync := zapcore.AddSync(os.Stdout)
 		outCore = zapcore.NewCore(
-			zConfig,
+			stdoutEncoder,
 			writeSync,
-			l.level,
+			zapcore.DebugLevel,
 		)
 	}
 
+	outCore = newLevelCore(outCore, c)
+
 	op1 := zap.AddCaller()
 
-	// we wrap 1 layer
-	op2 := zap.AddCallerSkip(1)
+	// we wrap 1 layer, and the log method is 1 more layer inside
+	op2 := zap.AddCallerSkip(1 + 1)
 
-	if l.skip > 0 {
-		op2 = zap.AddCallerSkip(l.skip)
+	if c.skip > 0 {
+		op2 = zap.AddCallerSkip(c.skip + 1)
 	}
 
 	zapLogger := zap.New(outCore, op1, op2)
-	if l.name != "" {
-		zapLogger = zapLogger.Named(l.name)
+	if c.name != "" {
+		zapLogger = zapLogger.Named(c.name)
 	}
-	sugarLogger := zapLogger.Sugar()
-	l.zapLogger = zapLogger
-	l.sugarLog = sugarLogger
+	return zapLogger, closers, nil
+}
+
+// validate check all the config and report every problem found
+func (c config) validate() error {
+	problems := new(ConfigError)
+
+	if level := c.level.Level(); level < DebugLevel || level > FatalLevel {
+		problems.add("level", level, errors.New("unknown level"))
+	}
+
+	if c.fileMaxAge < 0 {
+		problems.add("fileMaxAge", c.fileMaxAge, errors.New("must not be negative"))
+	}
+
+	if c.fileRotation < 0 {
+		problems.add("fileRotation", c.fileRotation, errors.New("must not be negative"))
+	} else if c.fileRotation > 0 && c.fileRotation < time.Minute {
+		problems.add("fileRotation", c.fileRotation, errors.New("must be at least one minute"))
+	}
+
+	if c.fileMaxSize < 0 {
+		problems.add("fileMaxSize", c.fileMaxSize, errors.New("must not be negative"))
+	}
+
+	if c.fileMaxBackups < 0 {
+		problems.add("fileMaxBackups", c.fileMaxBackups, errors.New("must not be negative"))
+	}
+
+	if c.logPathMaxSize < 0 {
+		problems.add("logPathMaxSize", c.logPathMaxSize, errors.New("must not be negative"))
+	}
+
+	if _, ok := getCompressor(c.fileCompress); c.fileCompress != "" && !ok {
+		problems.add("fileCompress", c.fileCompress, errors.New("unknown compress"))
+	}
+
+	validateOutputs(problems, c.outputs, c.logPath, c.fileName)
+	if c.color != ColorAuto && c.color != ColorAlways && c.color != ColorNever {
+		problems.add("color", c.color, errors.New("unknown color"))
+	}
+
+	if !validEncoder(c.stdoutEncoder) {
+		problems.add("stdoutEncoder", c.stdoutEncoder, errors.New("unknown encoder"))
+	}
+
+	if !validEncoder(c.fileEncoder) {
+		problems.add("fileEncoder", c.fileEncoder, errors.New("unknown encoder"))
+	}
+
+	if c.singleFile && len(c.outputs) > 0 {
+		problems.add("singleFile", c.singleFile, errors.New("can not be used with outputs"))
+	}
+
+	if c.logPath != "" {
+		if err := checkLogPath(c.logPath); err != nil {
+			problems.add("logPath", c.logPath, err)
+		}
+	}
+
+	if len(problems.Problems) > 0 {
+		return problems
+	}
+
+	return nil
 }
 
+// checkLogPath make sure the dir exist and can be written
+func checkLogPath(logPath string) error {
+	if err := os.MkdirAll(logPath, 0755); err != nil {
+		return err
+	}
+
+	f, err := ioutil.TempFile(logPath, ".golog_check_*")
+	if err != nil {
+		return err
+	}
+
+	f.Close()
+	return os.Remove(f.Name())
+}
+
+// InitLogger init the default logger, panic when config is invalid
 func InitLogger() {
 	_log.InitLogger()
 }
 
-func getWriter(isOutputStdout bool, filename string, maxAge, rotation time.Duration) io.Writer {
+// InitLoggerE init the default logger, return the error when config is invalid
+func InitLoggerE() error {
+	return _log.InitLoggerE()
+}
+
+func getWriter(isOutputStdout bool, filename string, maxAge, rotation time.Duration, opts ...rotateLogs.Option) (io.Writer, error) {
 	if maxAge <= 0 {
 		maxAge = 30 * 24 * time.Hour
 	}
@@ -216,25 +445,29 @@ func getWriter(isOutputStdout bool, filename string, maxAge, rotation time.Durat
 		format = "%Y%m%d.log"
 	}
 
-	hook, err := rotateLogs.New(
-		filename+"."+format,
+	opts = append([]rotateLogs.Option{
 		rotateLogs.WithLinkName(filename),
 		rotateLogs.WithMaxAge(maxAge),
 		rotateLogs.WithRotationTime(rotation),
-	)
+	}, opts...)
+
+	hook, err := newFileWriter(filename+"."+format, opts...)
 
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 
 	if isOutputStdout {
-		return io.MultiWriter(os.Stdout, hook)
+		return io.MultiWriter(os.Stdout, hook), nil
 	}
 
-	return hook
+	return hook, nil
 }
 
 func (l *logger) Sync() error {
+	l.coreMu.RLock()
+	defer l.coreMu.RUnlock()
+
 	return l.sugarLog.Sync()
 }
 
@@ -247,6 +480,9 @@ func SetName(name string) LoggerInterface {
 }
 
 func (l *logger) SetName(name string) LoggerInterface {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
 	l.name = name
 	return l
 }
@@ -255,8 +491,19 @@ func GetName() (name string) {
 	return _log.GetName()
 }
 
+// GetName the child return the full name joined by dot
 func (l *logger) GetName() (name string) {
-	return l.name
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	if l.named == "" {
+		return l.name
+	}
+
+	if l.name == "" {
+		return l.named
+	}
+	return l.name + "." + l.named
 }
 
 func SetCallerCallerSkip(skip int) LoggerInterface {
@@ -264,6 +511,9 @@ func SetCallerCallerSkip(skip int) LoggerInterface {
 }
 
 func (l *logger) SetCallerSkip(skip int) LoggerInterface {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
 	l.skip = skip
 	return l
 }
@@ -273,6 +523,9 @@ func GetCallerSkip() (skip int) {
 }
 
 func (l *logger) GetCallerSkip() (skip int) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
 	return l.skip
 }
 
@@ -281,6 +534,9 @@ func SetIsOutputStdout(isOutputStdout bool) LoggerInterface {
 }
 
 func (l *logger) SetIsOutputStdout(isOutputStdout bool) LoggerInterface {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
 	l.isOutputStdout = isOutputStdout
 	return l
 }
@@ -290,6 +546,9 @@ func GetIsOutputStdout() (isOutputStdout bool) {
 }
 
 func (l *logger) GetIsOutputStdout() (isOutputStdout bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
 	return l.isOutputStdout
 }
 
@@ -298,6 +557,9 @@ func SetFileRotate(fileMaxAge, fileRotation time.Duration) LoggerInterface {
 }
 
 func (l *logger) SetFileRotate(fileMaxAge, fileRotation time.Duration) LoggerInterface {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
 	l.fileMaxAge = fileMaxAge
 	l.fileRotation = fileRotation
 	return l
@@ -308,14 +570,272 @@ func GetFileRotate() (fileMaxAge, fileRotation time.Duration) {
 }
 
 func (l *logger) GetFileRotate() (fileMaxAge, fileRotation time.Duration) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
 	return l.fileMaxAge, l.fileRotation
 }
 
+// SetFileMaxSize rotate the file when its size reach maxSize bytes, or the rotation time is up, whichever first,
+// 0 means no limit
+func SetFileMaxSize(maxSize int64) LoggerInterface {
+	return _log.SetFileMaxSize(maxSize)
+}
+
+func (l *logger) SetFileMaxSize(maxSize int64) LoggerInterface {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.fileMaxSize = maxSize
+	return l
+}
+
+func GetFileMaxSize() (maxSize int64) {
+	return _log.GetFileMaxSize()
+}
+
+func (l *logger) GetFileMaxSize() (maxSize int64) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return l.fileMaxSize
+}
+
+// SetFileMaxBackups keep at most maxBackups rotated files for each log file, besides the fileMaxAge,
+// 0 means no limit
+func SetFileMaxBackups(maxBackups int) LoggerInterface {
+	return _log.SetFileMaxBackups(maxBackups)
+}
+
+func (l *logger) SetFileMaxBackups(maxBackups int) LoggerInterface {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.fileMaxBackups = maxBackups
+	return l
+}
+
+func GetFileMaxBackups() (maxBackups int) {
+	return _log.GetFileMaxBackups()
+}
+
+func (l *logger) GetFileMaxBackups() (maxBackups int) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return l.fileMaxBackups
+}
+
+// SetLogPathMaxSize the disk budget of the log path, when all the files exceed maxSize bytes
+// the oldest rotated files are removed, the files in use are never removed, 0 means no limit
+func SetLogPathMaxSize(maxSize int64) LoggerInterface {
+	return _log.SetLogPathMaxSize(maxSize)
+}
+
+func (l *logger) SetLogPathMaxSize(maxSize int64) LoggerInterface {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.logPathMaxSize = maxSize
+	return l
+}
+
+func GetLogPathMaxSize() (maxSize int64) {
+	return _log.GetLogPathMaxSize()
+}
+
+func (l *logger) GetLogPathMaxSize() (maxSize int64) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return l.logPathMaxSize
+}
+
+// SetFileCompress compress the rotated files in background by CompressGzip or the one added by RegisterCompressor,
+// the file in use and the link are never touched, empty means no compress
+func SetFileCompress(compress string) LoggerInterface {
+	return _log.SetFileCompress(compress)
+}
+
+func (l *logger) SetFileCompress(compress string) LoggerInterface {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.fileCompress = compress
+	return l
+}
+
+func GetFileCompress() (compress string) {
+	return _log.GetFileCompress()
+}
+
+func (l *logger) GetFileCompress() (compress string) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return l.fileCompress
+}
+
+// SetOutputs route the entries to the files under the log path of SetOutputFile by level instead of
+// the default debug/info/warn/error files, eg: one combined file, a file for each level or errors only,
+// take effect after InitLogger, call it without outputs to use the default
+func SetOutputs(outputs ...Output) LoggerInterface {
+	return _log.SetOutputs(outputs...)
+}
+
+func (l *logger) SetOutputs(outputs ...Output) LoggerInterface {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.outputs = append([]Output(nil), outputs...)
+	return l
+}
+
+func GetOutputs() []Output {
+	return _log.GetOutputs()
+}
+
+func (l *logger) GetOutputs() []Output {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return append([]Output(nil), l.fileOutputs()...)
+}
+
+// SetOutputSingleFile write all the entries at or above the level to one file app.log, or <fileName>.log,
+// under the log path instead of the four files, rotated by SetFileRotate, take effect after InitLogger
+func SetOutputSingleFile(single bool) LoggerInterface {
+	return _log.SetOutputSingleFile(single)
+}
+
+func (l *logger) SetOutputSingleFile(single bool) LoggerInterface {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.singleFile = single
+	return l
+}
+
+func GetOutputSingleFile() (single bool) {
+	return _log.GetOutputSingleFile()
+}
+
+func (l *logger) GetOutputSingleFile() (single bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return l.singleFile
+}
+
+// SetOutputColor color the level of the console output on stdout by ColorAuto, ColorAlways or ColorNever,
+// ColorAuto color only when stdout is a terminal, the files are never colored, take effect after InitLogger
+func SetOutputColor(color string) LoggerInterface {
+	return _log.SetOutputColor(color)
+}
+
+func (l *logger) SetOutputColor(color string) LoggerInterface {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.color = color
+	return l
+}
+
+func GetOutputColor() (color string) {
+	return _log.GetOutputColor()
+}
+
+func (l *logger) GetOutputColor() (color string) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return l.color
+}
+
+// SetStdoutEncoder the encoder of stdout, EncoderJSON or EncoderConsole, empty follow SetOutputJson,
+// eg: console on the terminal while the files are json, take effect after InitLogger
+func SetStdoutEncoder(encoder string) LoggerInterface {
+	return _log.SetStdoutEncoder(encoder)
+}
+
+func (l *logger) SetStdoutEncoder(encoder string) LoggerInterface {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.stdoutEncoder = encoder
+	return l
+}
+
+func GetStdoutEncoder() (encoder string) {
+	return _log.GetStdoutEncoder()
+}
+
+func (l *logger) GetStdoutEncoder() (encoder string) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return l.stdoutEncoder
+}
+
+// SetFileEncoder the encoder of the files, EncoderJSON or EncoderConsole, empty follow SetOutputJson,
+// Output.Encoder override it for the output, take effect after InitLogger
+func SetFileEncoder(encoder string) LoggerInterface {
+	return _log.SetFileEncoder(encoder)
+}
+
+func (l *logger) SetFileEncoder(encoder string) LoggerInterface {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.fileEncoder = encoder
+	return l
+}
+
+func GetFileEncoder() (encoder string) {
+	return _log.GetFileEncoder()
+}
+
+func (l *logger) GetFileEncoder() (encoder string) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return l.fileEncoder
+}
+
+// SetAccessLog the logger named AccessLoggerName, eg: used by HTTPMiddleware, only write access.log,
+// or <fileName>_access.log, take effect after InitLogger, access.log is not written without it,
+// the debug entries go to debug.log, which was named access.log before
+func SetAccessLog(accessLog bool) LoggerInterface {
+	return _log.SetAccessLog(accessLog)
+}
+
+func (l *logger) SetAccessLog(accessLog bool) LoggerInterface {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.accessLog = accessLog
+	return l
+}
+
+func GetAccessLog() (accessLog bool) {
+	return _log.GetAccessLog()
+}
+
+func (l *logger) GetAccessLog() (accessLog bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return l.accessLog
+}
+
 func SetCallerShort(short bool) LoggerInterface {
 	return _log.SetCallerShort(short)
 }
 
 func (l *logger) SetCallerShort(short bool) LoggerInterface {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
 	l.short = short
 	return l
 }
@@ -325,6 +845,9 @@ func GetCallerShort() (short bool) {
 }
 
 func (l *logger) GetCallerShort() (short bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
 	return l.short
 }
 
@@ -333,6 +856,9 @@ func SetOutputJson(json bool) LoggerInterface {
 }
 
 func (l *logger) SetOutputJson(json bool) LoggerInterface {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
 	l.json = json
 	return l
 }
@@ -342,6 +868,9 @@ func GetOutputJson() (json bool) {
 }
 
 func (l *logger) GetOutputJson() (json bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
 	return l.json
 }
 
@@ -349,20 +878,77 @@ func SetLevel(level Level) LoggerInterface {
 	return _log.SetLevel(level)
 }
 
+// SetLevel take effect at once, no need to call InitLogger again
 func (l *logger) SetLevel(level Level) LoggerInterface {
-	l.level = level
+	l.level.SetLevel(level)
+	return l
+}
+
+// SetLevelOverride set the level of the named logger, eg: billing, billing.sql, take effect at once
+func SetLevelOverride(name string, level Level) LoggerInterface {
+	return _log.SetLevelOverride(name, level)
+}
+
+func (l *logger) SetLevelOverride(name string, level Level) LoggerInterface {
+	l.levels.set(name, level)
+	return l
+}
+
+func RemoveLevelOverride(name string) LoggerInterface {
+	return _log.RemoveLevelOverride(name)
+}
+
+func (l *logger) RemoveLevelOverride(name string) LoggerInterface {
+	l.levels.remove(name)
 	return l
 }
 
+func GetLevelOverrides() map[string]Level {
+	return _log.GetLevelOverrides()
+}
+
+func (l *logger) GetLevelOverrides() map[string]Level {
+	return l.levels.all()
+}
+
+// SetLevelSpec set the level and replace all the level overrides by spec like "info,billing=debug,billing.sql=warn"
+func SetLevelSpec(spec string) error {
+	return _log.SetLevelSpec(spec)
+}
+
+func (l *logger) SetLevelSpec(spec string) error {
+	level, hasLevel, levels, err := parseLevelSpec(spec)
+	if err != nil {
+		return err
+	}
+
+	if hasLevel {
+		l.level.SetLevel(level)
+	}
+	l.levels.replace(levels)
+	return nil
+}
+
+func GetLevelSpec() string {
+	return _log.GetLevelSpec()
+}
+
+func (l *logger) GetLevelSpec() string {
+	return levelSpec(l.level.Level(), l.levels.all())
+}
+
 func GetLevel() (level Level) {
 	return _log.GetLevel()
 }
 
 func (l *logger) GetLevel() (level Level) {
-	return l.level
+	return l.level.Level()
 }
 
 func (l *logger) SetOutputFile(logPath, fileName string) LoggerInterface {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
 	l.logPath = logPath
 	l.fileName = fileName
 	if l.fileMaxAge == 0 {
@@ -377,6 +963,9 @@ func SetOutputFile(logPath, fileName string) LoggerInterface {
 }
 
 func (l *logger) GetOutputFile() (logPath, fileName string) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
 	return l.logPath, l.fileName
 }
 
@@ -385,7 +974,7 @@ func GetOutputFile() (logPath, fileName string) {
 }
 
 func (l *logger) Fatalf(template string, args ...interface{}) {
-	l.sugarLog.Fatalf(template, args...)
+	l.log(FatalLevel, nil, template, args)
 }
 
 func Fatalf(template string, args ...interface{}) {
@@ -393,7 +982,7 @@ func Fatalf(template string, args ...interface{}) {
 }
 
 func (l *logger) Fatal(args ...interface{}) {
-	l.sugarLog.Fatal(args...)
+	l.log(FatalLevel, nil, "", args)
 }
 
 func Fatal(args ...interface{}) {
@@ -401,7 +990,7 @@ func Fatal(args ...interface{}) {
 }
 
 func (l *logger) Panicf(template string, args ...interface{}) {
-	l.sugarLog.With().Panicf(template, args...)
+	l.log(PanicLevel, nil, template, args)
 }
 
 func Panicf(template string, args ...interface{}) {
@@ -409,7 +998,7 @@ func Panicf(template string, args ...interface{}) {
 }
 
 func (l *logger) Panic(args ...interface{}) {
-	l.sugarLog.Panic(args...)
+	l.log(PanicLevel, nil, "", args)
 }
 
 func Panic(args ...interface{}) {
@@ -417,7 +1006,7 @@ func Panic(args ...interface{}) {
 }
 
 func (l *logger) Errorf(template string, args ...interface{}) {
-	l.sugarLog.Errorf(template, args...)
+	l.log(ErrorLevel, nil, template, args)
 }
 
 func Errorf(template string, args ...interface{}) {
@@ -425,7 +1014,7 @@ func Errorf(template string, args ...interface{}) {
 }
 
 func (l *logger) Error(args ...interface{}) {
-	l.sugarLog.Error(args...)
+	l.log(ErrorLevel, nil, "", args)
 }
 
 func Error(args ...interface{}) {
@@ -433,7 +1022,7 @@ func Error(args ...interface{}) {
 }
 
 func (l *logger) Warnf(template string, args ...interface{}) {
-	l.sugarLog.Warnf(template, args...)
+	l.log(WarnLevel, nil, template, args)
 }
 
 func Warnf(template string, args ...interface{}) {
@@ -441,7 +1030,7 @@ func Warnf(template string, args ...interface{}) {
 }
 
 func (l *logger) Warn(args ...interface{}) {
-	l.sugarLog.Warn(args...)
+	l.log(WarnLevel, nil, "", args)
 }
 
 func Warn(args ...interface{}) {
@@ -449,7 +1038,7 @@ func Warn(args ...interface{}) {
 }
 
 func (l *logger) Infof(template string, args ...interface{}) {
-	l.sugarLog.Infof(template, args...)
+	l.log(InfoLevel, nil, template, args)
 }
 
 func Infof(template string, args ...interface{}) {
@@ -457,7 +1046,7 @@ func Infof(template string, args ...interface{}) {
 }
 
 func (l *logger) Info(args ...interface{}) {
-	l.sugarLog.Info(args...)
+	l.log(InfoLevel, nil, "", args)
 }
 
 func Info(args ...interface{}) {
@@ -465,7 +1054,7 @@ func Info(args ...interface{}) {
 }
 
 func (l *logger) Debugf(template string, args ...interface{}) {
-	l.sugarLog.Debugf(template, args...)
+	l.log(DebugLevel, nil, template, args)
 }
 
 func Debugf(template string, args ...interface{}) {
@@ -473,13 +1062,52 @@ func Debugf(template string, args ...interface{}) {
 }
 
 func (l *logger) Debug(args ...interface{}) {
-	l.sugarLog.Debug(args...)
+	l.log(DebugLevel, nil, "", args)
 }
 
 func Debug(args ...interface{}) {
 	_log.Debug(args...)
 }
 
+// log all the level methods come here, hold the read lock so InitLogger can not close the cores in use
+func (l *logger) log(lvl Level, fields []interface{}, template string, args []interface{}) {
+	l.coreMu.RLock()
+	defer l.coreMu.RUnlock()
+
+	_, s := l.current()
+	if len(fields) > 0 {
+		s = s.With(fields...)
+	}
+
+	switch lvl {
+	case DebugLevel:
+		s.Debugf(template, args...)
+	case InfoLevel:
+		s.Infof(template, args...)
+	case WarnLevel:
+		s.Warnf(template, args...)
+	case ErrorLevel:
+		s.Errorf(template, args...)
+	case PanicLevel:
+		s.Panicf(template, args...)
+	case FatalLevel:
+		s.Fatalf(template, args...)
+	}
+}
+
+// logForceDebug same as log at debug level but ignore the level, the entry go to the debug file and stdout
+func (l *logger) logForceDebug(fields []interface{}, template string, args []interface{}) {
+	l.coreMu.RLock()
+	defer l.coreMu.RUnlock()
+
+	z, _ := l.current()
+	s := z.WithOptions(zap.WrapCore(forceCore)).Sugar()
+	if len(fields) > 0 {
+		s = s.With(fields...)
+	}
+	s.Debugf(template, args...)
+}
+
 func with(fields map[string]interface{}) []interface{} {
 	i := make([]interface{}, 0, 2*len(fields))
 	keys := make([]string, 0, len(fields))
@@ -495,11 +1123,7 @@ func with(fields map[string]interface{}) []interface{} {
 }
 
 func (l *logger) DebugWithFields(fields map[string]interface{}, template string, args ...interface{}) {
-	if len(args) == 0 {
-		l.sugarLog.With(with(fields)...).Debug(template)
-		return
-	}
-	l.sugarLog.With(with(fields)...).Debugf(template, args...)
+	l.log(DebugLevel, with(fields), template, args)
 }
 
 func DebugWithFields(fields map[string]interface{}, template string, args ...interface{}) {
@@ -507,11 +1131,7 @@ func DebugWithFields(fields map[string]interface{}, template string, args ...int
 }
 
 func (l *logger) InfoWithFields(fields map[string]interface{}, template string, args ...interface{}) {
-	if len(args) == 0 {
-		l.sugarLog.With(with(fields)...).Info(template)
-		return
-	}
-	l.sugarLog.With(with(fields)...).Infof(template, args...)
+	l.log(InfoLevel, with(fields), template, args)
 }
 
 func InfoWithFields(fields map[string]interface{}, template string, args ...interface{}) {
@@ -519,11 +1139,7 @@ func InfoWithFields(fields map[string]interface{}, template string, args ...inte
 }
 
 func (l *logger) WarnWithFields(fields map[string]interface{}, template string, args ...interface{}) {
-	if len(args) == 0 {
-		l.sugarLog.With(with(fields)...).Warn(template)
-		return
-	}
-	l.sugarLog.With(with(fields)...).Warnf(template, args...)
+	l.log(WarnLevel, with(fields), template, args)
 }
 
 func WarnWithFields(fields map[string]interface{}, template string, args ...interface{}) {
@@ -531,11 +1147,7 @@ func WarnWithFields(fields map[string]interface{}, template string, args ...inte
 }
 
 func (l *logger) ErrorWithFields(fields map[string]interface{}, template string, args ...interface{}) {
-	if len(args) == 0 {
-		l.sugarLog.With(with(fields)...).Error(template)
-		return
-	}
-	l.sugarLog.With(with(fields)...).Errorf(template, args...)
+	l.log(ErrorLevel, with(fields), template, args)
 }
 
 func ErrorWithFields(fields map[string]interface{}, template string, args ...interface{}) {
@@ -543,11 +1155,7 @@ func ErrorWithFields(fields map[string]interface{}, template string, args ...int
 }
 
 func (l *logger) FatalWithFields(fields map[string]interface{}, template string, args ...interface{}) {
-	if len(args) == 0 {
-		l.sugarLog.With(with(fields)...).Fatal(template)
-		return
-	}
-	l.sugarLog.With(with(fields)...).Fatalf(template, args...)
+	l.log(FatalLevel, with(fields), template, args)
 }
 
 func FatalWithFields(fields map[string]interface{}, template string, args ...interface{}) {
@@ -555,199 +1163,225 @@ func FatalWithFields(fields map[string]interface{}, template string, args ...int
 }
 
 func (l *logger) PanicWithFields(fields map[string]interface{}, template string, args ...interface{}) {
-	if len(args) == 0 {
-		l.sugarLog.With(with(fields)...).Panic(template)
-		return
-	}
-	l.sugarLog.With(with(fields)...).Panicf(template, args...)
+	l.log(PanicLevel, with(fields), template, args)
 }
 
 func PanicWithFields(fields map[string]interface{}, template string, args ...interface{}) {
 	_log.PanicWithFields(fields, template, args...)
 }
 
+// FieldFunc extract the fields from context, used by the *Context methods
+type FieldFunc func(context.Context, map[string]interface{})
+
+type namedFieldFunc struct {
+	name string
+	f    FieldFunc
+}
+
+// AddFieldFunc append f to the field funcs, they run by the order added
 func AddFieldFunc(f func(context.Context, map[string]interface{})) {
 	_log.AddFieldFunc(f)
 }
 
 func (l *logger) AddFieldFunc(f func(context.Context, map[string]interface{})) {
-	l.addFieldFunc = f
-	return
+	l.SetFieldFunc("", f)
+}
+
+// SetFieldFunc replace the field func with the same name in place, or append it when not found,
+// empty name always append
+func SetFieldFunc(name string, f FieldFunc) LoggerInterface {
+	return _log.SetFieldFunc(name, f)
 }
 
-func (l *logger) addField(ctx context.Context, fields map[string]interface{}) {
-	//fields["service.log.name"] = l.name
-	//fields["service.log.time"] = time.Now().String()
+func (l *logger) SetFieldFunc(name string, f FieldFunc) LoggerInterface {
+	l.mu.Lock()
+	defer l.mu.Unlock()
 
-	if l.addFieldFunc != nil {
-		l.addFieldFunc(ctx, fields)
+	fieldFuncs := make([]namedFieldFunc, 0, len(l.fieldFuncs)+1)
+	replaced := false
+	for _, v := range l.fieldFuncs {
+		if name != "" && v.name == name {
+			v.f = f
+			replaced = true
+		}
+		fieldFuncs = append(fieldFuncs, v)
 	}
+
+	if !replaced {
+		fieldFuncs = append(fieldFuncs, namedFieldFunc{name: name, f: f})
+	}
+
+	l.fieldFuncs = fieldFuncs
+	return l
+}
+
+// RemoveFieldFunc remove the field func added by SetFieldFunc
+func RemoveFieldFunc(name string) LoggerInterface {
+	return _log.RemoveFieldFunc(name)
+}
+
+func (l *logger) RemoveFieldFunc(name string) LoggerInterface {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	fieldFuncs := make([]namedFieldFunc, 0, len(l.fieldFuncs))
+	for _, v := range l.fieldFuncs {
+		if v.name != name {
+			fieldFuncs = append(fieldFuncs, v)
+		}
+	}
+
+	l.fieldFuncs = fieldFuncs
+	return l
 }
 
+// addField run all the field funcs by order, the later one overwrite the former one,
+// but the key already in fields given by caller always win, the result is a new map when any added
+// so the map of the caller is never modified and can be reused
+func (l *logger) addField(ctx context.Context, fields map[string]interface{}) map[string]interface{} {
+	l.mu.RLock()
+	fieldFuncs := l.fieldFuncs
+	l.mu.RUnlock()
+
+	if len(fieldFuncs) == 0 {
+		return fields
+	}
+
+	merged := make(map[string]interface{}, len(fields))
+	for _, v := range fieldFuncs {
+		v.f(ctx, merged)
+	}
+
+	for k, v := range fields {
+		merged[k] = v
+	}
+	return merged
+}
+
+// DebugContextWithFields write even the level is above debug when ctx marked by ForceDebug
 func (l *logger) DebugContextWithFields(ctx context.Context, fields map[string]interface{}, template string, args ...interface{}) {
-	l.addField(ctx, fields)
-	if len(args) == 0 {
-		l.sugarLog.With(with(fields)...).Debug(template)
+	fields = l.addField(ctx, fields)
+	if IsForceDebug(ctx) {
+		l.logForceDebug(with(fields), template, args)
 		return
 	}
-	l.sugarLog.With(with(fields)...).Debugf(template, args...)
+	l.log(DebugLevel, with(fields), template, args)
 }
 
 func DebugContextWithFields(ctx context.Context, fields map[string]interface{}, template string, args ...interface{}) {
-	_log.DebugContextWithFields(ctx, fields, template, args...)
+	ctxPkgLogger(ctx).DebugContextWithFields(ctx, fields, template, args...)
 }
 
 func (l *logger) InfoContextWithFields(ctx context.Context, fields map[string]interface{}, template string, args ...interface{}) {
-	l.addField(ctx, fields)
-	if len(args) == 0 {
-		l.sugarLog.With(with(fields)...).Info(template)
-		return
-	}
-	l.sugarLog.With(with(fields)...).Infof(template, args...)
+	fields = l.addField(ctx, fields)
+	l.log(InfoLevel, with(fields), template, args)
 }
 
 func InfoContextWithFields(ctx context.Context, fields map[string]interface{}, template string, args ...interface{}) {
-	_log.InfoContextWithFields(ctx, fields, template, args...)
+	ctxPkgLogger(ctx).InfoContextWithFields(ctx, fields, template, args...)
 }
 
 func (l *logger) WarnContextWithFields(ctx context.Context, fields map[string]interface{}, template string, args ...interface{}) {
-	l.addField(ctx, fields)
-	if len(args) == 0 {
-		l.sugarLog.With(with(fields)...).Warn(template)
-		return
-	}
-	l.sugarLog.With(with(fields)...).Warnf(template, args...)
+	fields = l.addField(ctx, fields)
+	l.log(WarnLevel, with(fields), template, args)
 }
 
 func WarnContextWithFields(ctx context.Context, fields map[string]interface{}, template string, args ...interface{}) {
-	_log.WarnContextWithFields(ctx, fields, template, args...)
+	ctxPkgLogger(ctx).WarnContextWithFields(ctx, fields, template, args...)
 }
 
 func (l *logger) ErrorContextWithFields(ctx context.Context, fields map[string]interface{}, template string, args ...interface{}) {
-	l.addField(ctx, fields)
-	if len(args) == 0 {
-		l.sugarLog.With(with(fields)...).Error(template)
-		return
-	}
-	l.sugarLog.With(with(fields)...).Errorf(template, args...)
+	fields = l.addField(ctx, fields)
+	l.log(ErrorLevel, with(fields), template, args)
 }
 
 func ErrorContextWithFields(ctx context.Context, fields map[string]interface{}, template string, args ...interface{}) {
-	_log.ErrorContextWithFields(ctx, fields, template, args...)
+	ctxPkgLogger(ctx).ErrorContextWithFields(ctx, fields, template, args...)
 }
 
 func (l *logger) FatalContextWithFields(ctx context.Context, fields map[string]interface{}, template string, args ...interface{}) {
-	l.addField(ctx, fields)
-	if len(args) == 0 {
-		l.sugarLog.With(with(fields)...).Fatal(template)
-		return
-	}
-	l.sugarLog.With(with(fields)...).Fatalf(template, args...)
+	fields = l.addField(ctx, fields)
+	l.log(FatalLevel, with(fields), template, args)
 }
 
 func FatalContextWithFields(ctx context.Context, fields map[string]interface{}, template string, args ...interface{}) {
-	_log.FatalContextWithFields(ctx, fields, template, args...)
+	ctxPkgLogger(ctx).FatalContextWithFields(ctx, fields, template, args...)
 }
 
 func (l *logger) PanicContextWithFields(ctx context.Context, fields map[string]interface{}, template string, args ...interface{}) {
-	l.addField(ctx, fields)
-	if len(args) == 0 {
-		l.sugarLog.With(with(fields)...).Panic(template)
-		return
-	}
-	l.sugarLog.With(with(fields)...).Panicf(template, args...)
+	fields = l.addField(ctx, fields)
+	l.log(PanicLevel, with(fields), template, args)
 }
 
 func PanicContextWithFields(ctx context.Context, fields map[string]interface{}, template string, args ...interface{}) {
-	_log.PanicContextWithFields(ctx, fields, template, args...)
+	ctxPkgLogger(ctx).PanicContextWithFields(ctx, fields, template, args...)
 }
 
+// DebugContext write even the level is above debug when ctx marked by ForceDebug
 func (l *logger) DebugContext(ctx context.Context, template string, args ...interface{}) {
-	fields := make(map[string]interface{})
-	l.addField(ctx, fields)
-	if len(args) == 0 {
-		l.sugarLog.With(with(fields)...).Debug(template)
+	fields := l.addField(ctx, nil)
+	if IsForceDebug(ctx) {
+		l.logForceDebug(with(fields), template, args)
 		return
 	}
-	l.sugarLog.With(with(fields)...).Debugf(template, args...)
+	l.log(DebugLevel, with(fields), template, args)
 }
 
 func DebugContext(ctx context.Context, template string, args ...interface{}) {
-	_log.DebugContext(ctx, template, args...)
+	ctxPkgLogger(ctx).DebugContext(ctx, template, args...)
 }
 
 func (l *logger) InfoContext(ctx context.Context, template string, args ...interface{}) {
-	fields := make(map[string]interface{})
-	l.addField(ctx, fields)
-	if len(args) == 0 {
-		l.sugarLog.With(with(fields)...).Info(template)
-		return
-	}
-	l.sugarLog.With(with(fields)...).Infof(template, args...)
+	fields := l.addField(ctx, nil)
+	l.log(InfoLevel, with(fields), template, args)
 }
 
 func InfoContext(ctx context.Context, template string, args ...interface{}) {
-	_log.InfoContext(ctx, template, args...)
+	ctxPkgLogger(ctx).InfoContext(ctx, template, args...)
 }
 
 func (l *logger) WarnContext(ctx context.Context, template string, args ...interface{}) {
-	fields := make(map[string]interface{})
-	l.addField(ctx, fields)
-	if len(args) == 0 {
-		l.sugarLog.With(with(fields)...).Warn(template)
-		return
-	}
-	l.sugarLog.With(with(fields)...).Warnf(template, args...)
+	fields := l.addField(ctx, nil)
+	l.log(WarnLevel, with(fields), template, args)
 }
 
 func WarnContext(ctx context.Context, template string, args ...interface{}) {
-	_log.WarnContext(ctx, template, args...)
+	ctxPkgLogger(ctx).WarnContext(ctx, template, args...)
 }
 
 func (l *logger) ErrorContext(ctx context.Context, template string, args ...interface{}) {
-	fields := make(map[string]interface{})
-	l.addField(ctx, fields)
-	if len(args) == 0 {
-		l.sugarLog.With(with(fields)...).Error(template)
-		return
-	}
-	l.sugarLog.With(with(fields)...).Errorf(template, args...)
+	fields := l.addField(ctx, nil)
+	l.log(ErrorLevel, with(fields), template, args)
 }
 
 func ErrorContext(ctx context.Context, template string, args ...interface{}) {
-	_log.ErrorContext(ctx, template, args...)
+	ctxPkgLogger(ctx).ErrorContext(ctx, template, args...)
 }
 
 func (l *logger) FatalContext(ctx context.Context, template string, args ...interface{}) {
-	fields := make(map[string]interface{})
-	l.addField(ctx, fields)
-	if len(args) == 0 {
-		l.sugarLog.With(with(fields)...).Fatal(template)
-		return
-	}
-	l.sugarLog.With(with(fields)...).Fatalf(template, args...)
+	fields := l.addField(ctx, nil)
+	l.log(FatalLevel, with(fields), template, args)
 }
 
 func FatalContext(ctx context.Context, template string, args ...interface{}) {
-	_log.FatalContext(ctx, template, args...)
+	ctxPkgLogger(ctx).FatalContext(ctx, template, args...)
 }
 
 func (l *logger) PanicContext(ctx context.Context, template string, args ...interface{}) {
-	fields := make(map[string]interface{})
-	l.addField(ctx, fields)
-	if len(args) == 0 {
-		l.sugarLog.With(with(fields)...).Panic(template)
-		return
-	}
-	l.sugarLog.With(with(fields)...).Panicf(template, args...)
+	fields := l.addField(ctx, nil)
+	l.log(PanicLevel, with(fields), template, args)
 }
 
 func PanicContext(ctx context.Context, template string, args ...interface{}) {
-	_log.PanicContext(ctx, template, args...)
+	ctxPkgLogger(ctx).PanicContext(ctx, template, args...)
 }
 
 func (l *logger) GetZapLogger() *zap.Logger {
-	return l.zapLogger
+	l.coreMu.RLock()
+	defer l.coreMu.RUnlock()
+
+	z, _ := l.current()
+	return z
 }
 
 func GetZapLogger() *zap.Logger {
@@ -759,5 +1393,9 @@ func GetZapSugaredLogger() *zap.SugaredLogger {
 }
 
 func (l *logger) GetZapSugaredLogger() *zap.SugaredLogger {
-	return l.sugarLog
+	l.coreMu.RLock()
+	defer l.coreMu.RUnlock()
+
+	_, s := l.current()
+	return s
 }
@@ -2,7 +2,13 @@ package golog
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 )
@@ -61,3 +67,224 @@ func TestDebug(t *testing.T) {
 	FatalContext(ctx, "asdasd:%s", "adAD")
 	PanicContext(ctx, "asdasd:%s", "adAD")
 }
+
+func TestInitLoggerE(t *testing.T) {
+	dir := t.TempDir()
+	notDir := filepath.Join(dir, "file")
+	if err := ioutil.WriteFile(notDir, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	l := New()
+	l.SetOutputFile(dir, "ok")
+	if err := l.InitLoggerE(); err != nil {
+		t.Fatal(err)
+	}
+
+	before := l.GetZapLogger()
+	l.SetOutputFile(notDir, "bad").SetFileRotate(time.Hour, -time.Hour)
+	err := l.InitLoggerE()
+
+	configErr, ok := err.(*ConfigError)
+	if !ok {
+		t.Fatalf("want *ConfigError, got %v", err)
+	}
+
+	if len(configErr.Problems) != 2 {
+		t.Fatalf("want 2 problems, got %v", configErr)
+	}
+
+	if l.GetZapLogger() != before {
+		t.Fatal("previous logger should be kept when init fail")
+	}
+}
+
+func TestReInitConcurrent(t *testing.T) {
+	dir := t.TempDir()
+	l := New().SetOutputFile(dir, "race")
+	l.InitLogger()
+
+	ctx := context.Background()
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				l.Info("info")
+				l.Warnf("warn %d", 1)
+				l.ErrorWithFields(map[string]interface{}{"k": "v"}, "error")
+				l.InfoContext(ctx, "context")
+			}
+		}()
+	}
+
+	for i := 0; i < 20; i++ {
+		if i%2 == 0 {
+			l.SetLevel(DebugLevel).SetOutputFile(dir, "race_a")
+		} else {
+			l.SetLevel(WarnLevel).SetOutputFile(dir, "race_b")
+		}
+		n := i
+		l.AddFieldFunc(func(ctx context.Context, m map[string]interface{}) {
+			m["i"] = n
+		})
+		if err := l.InitLoggerE(); err != nil {
+			t.Fatal(err)
+		}
+		_ = l.GetLevel()
+	}
+
+	close(stop)
+	wg.Wait()
+
+	if err := l.Sync(); err != nil {
+		t.Log(err)
+	}
+}
+
+func TestSetLevelAtOnce(t *testing.T) {
+	dir := t.TempDir()
+	l := New().SetOutputFile(dir, "level")
+	l.InitLogger()
+
+	debugFile := filepath.Join(dir, "level_debug.log")
+	l.Debug("should not output")
+	if _, err := os.Stat(debugFile); err == nil {
+		t.Fatal("debug file should not be written at info level")
+	}
+
+	l.SetLevel(DebugLevel)
+	if l.GetLevel() != DebugLevel {
+		t.Fatalf("want debug level, got %s", l.GetLevel())
+	}
+
+	l.Debug("should output")
+	b, err := ioutil.ReadFile(debugFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(string(b), "should output") {
+		t.Fatalf("debug entry not found: %s", b)
+	}
+
+	// at warn level the debug and info files get nothing, same as they are not created
+	l.SetLevel(WarnLevel)
+	l.Info("info at warn")
+	l.Warn("warn at warn")
+	for file, want := range map[string]bool{"level_debug.log": false, "level_info.log": false, "level_warn.log": true} {
+		b, _ := ioutil.ReadFile(filepath.Join(dir, file))
+		if strings.Contains(string(b), "info at warn") || strings.Contains(string(b), "warn at warn") != want {
+			t.Errorf("%s want warn entry %v: %s", file, want, b)
+		}
+	}
+}
+
+func TestTypedFields(t *testing.T) {
+	dir := t.TempDir()
+	l := New().SetOutputFile(dir, "typed").SetOutputJson(true).SetCallerShort(true)
+	l.InitLogger()
+
+	l.InfoF("typed", String("b", "x"), Int64("a", 1), Duration("cost", time.Second), Err(errors.New("boom")))
+
+	b, err := ioutil.ReadFile(filepath.Join(dir, "typed_info.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `"msg":"typed","b":"x","a":1,"cost":1,"error":"boom"`
+	if !strings.Contains(string(b), want) || !strings.Contains(string(b), "golog_test.go") {
+		t.Fatalf("want %s in %s", want, b)
+	}
+}
+
+func benchmarkLogger(b *testing.B) LoggerInterface {
+	l := New().SetOutputFile(b.TempDir(), "bench").SetOutputJson(true)
+	l.InitLogger()
+	return l
+}
+
+func BenchmarkInfoWithFields(b *testing.B) {
+	l := benchmarkLogger(b)
+	err := errors.New("boom")
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.InfoWithFields(map[string]interface{}{
+			"user":  "jack",
+			"count": int64(i),
+			"cost":  time.Millisecond,
+			"error": err,
+		}, "request done")
+	}
+}
+
+func BenchmarkInfoF(b *testing.B) {
+	l := benchmarkLogger(b)
+	err := errors.New("boom")
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.InfoF("request done",
+			String("user", "jack"),
+			Int64("count", int64(i)),
+			Duration("cost", time.Millisecond),
+			Err(err),
+		)
+	}
+}
+
+func TestFieldFuncs(t *testing.T) {
+	l := New().(*logger)
+	l.SetFieldFunc("trace", func(ctx context.Context, m map[string]interface{}) {
+		m["trace"] = "t1"
+		m["k"] = "from trace"
+	})
+	l.SetFieldFunc("tenant", func(ctx context.Context, m map[string]interface{}) {
+		m["tenant"] = "a"
+		m["order"] = "tenant"
+	})
+	l.AddFieldFunc(func(ctx context.Context, m map[string]interface{}) {
+		m["order"] = "last"
+	})
+	l.SetFieldFunc("tenant", func(ctx context.Context, m map[string]interface{}) {
+		m["tenant"] = "b"
+	})
+
+	fields := map[string]interface{}{"k": "explicit"}
+	got := l.addField(context.Background(), fields)
+
+	want := map[string]interface{}{"k": "explicit", "trace": "t1", "tenant": "b", "order": "last"}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("want %v, got %v", want, got)
+	}
+
+	if len(fields) != 1 {
+		t.Fatalf("the map of caller should not be modified: %v", fields)
+	}
+
+	l.RemoveFieldFunc("trace")
+	got = l.addField(context.Background(), map[string]interface{}{})
+	if _, ok := got["trace"]; ok {
+		t.Fatalf("trace should be removed: %v", got)
+	}
+
+	// the map reused by caller get the value of each ctx
+	l.SetFieldFunc("ctx", func(ctx context.Context, m map[string]interface{}) {
+		m["ctx"] = ctx.Value("ctx")
+	})
+	reused := map[string]interface{}{}
+	for _, v := range []string{"A", "B"} {
+		got = l.addField(context.WithValue(context.Background(), "ctx", v), reused)
+		if got["ctx"] != v {
+			t.Fatalf("want ctx=%s, got %v", v, got)
+		}
+	}
+}
@@ -0,0 +1,293 @@
+// Package grpclog log the gRPC calls and the grpclog lines by golog, the interceptors write one entry per call
+// with the method, code, duration and size, the recovery interceptors turn a panic into codes.Internal,
+// and NewLogger is the grpclog.LoggerV2 of the gRPC library itself
+package grpclog
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hunterhug/golog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	grpcLog "google.golang.org/grpc/grpclog"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// NewLogger return a grpclog.DepthLoggerV2 write to l, set it by grpclog.SetLoggerV2,
+// the caller of the grpclog funcs is logged, V(n) is true for n <= 0, or any n when l enable debug
+func NewLogger(l golog.LoggerInterface) grpcLog.DepthLoggerV2 {
+	// the grpcLogger and the grpclog funcs are two more layers
+	return &grpcLogger{base: l, l: l.WithCallerSkip(2)}
+}
+
+type grpcLogger struct {
+	base golog.LoggerInterface
+	l    golog.LoggerInterface
+}
+
+// depth return the logger report the caller depth frames above the caller of the grpclog depth funcs
+func (g *grpcLogger) depth(depth int) golog.LoggerInterface {
+	return g.base.WithCallerSkip(2 + depth)
+}
+
+func sprintln(args []interface{}) string {
+	return strings.TrimSuffix(fmt.Sprintln(args...), "\n")
+}
+
+func (g *grpcLogger) Info(args ...interface{}) {
+	g.l.Info(args...)
+}
+
+func (g *grpcLogger) Infoln(args ...interface{}) {
+	g.l.Info(sprintln(args))
+}
+
+func (g *grpcLogger) Infof(format string, args ...interface{}) {
+	g.l.Infof(format, args...)
+}
+
+func (g *grpcLogger) Warning(args ...interface{}) {
+	g.l.Warn(args...)
+}
+
+func (g *grpcLogger) Warningln(args ...interface{}) {
+	g.l.Warn(sprintln(args))
+}
+
+func (g *grpcLogger) Warningf(format string, args ...interface{}) {
+	g.l.Warnf(format, args...)
+}
+
+func (g *grpcLogger) Error(args ...interface{}) {
+	g.l.Error(args...)
+}
+
+func (g *grpcLogger) Errorln(args ...interface{}) {
+	g.l.Error(sprintln(args))
+}
+
+func (g *grpcLogger) Errorf(format string, args ...interface{}) {
+	g.l.Errorf(format, args...)
+}
+
+func (g *grpcLogger) Fatal(args ...interface{}) {
+	g.l.Fatal(args...)
+}
+
+func (g *grpcLogger) Fatalln(args ...interface{}) {
+	g.l.Fatal(sprintln(args))
+}
+
+func (g *grpcLogger) Fatalf(format string, args ...interface{}) {
+	g.l.Fatalf(format, args...)
+}
+
+func (g *grpcLogger) InfoDepth(depth int, args ...interface{}) {
+	g.depth(depth).Info(sprintln(args))
+}
+
+func (g *grpcLogger) WarningDepth(depth int, args ...interface{}) {
+	g.depth(depth).Warn(sprintln(args))
+}
+
+func (g *grpcLogger) ErrorDepth(depth int, args ...interface{}) {
+	g.depth(depth).Error(sprintln(args))
+}
+
+func (g *grpcLogger) FatalDepth(depth int, args ...interface{}) {
+	g.depth(depth).Fatal(sprintln(args))
+}
+
+func (g *grpcLogger) V(level int) bool {
+	return level <= 0 || g.l.Enabled(golog.DebugLevel)
+}
+
+// grpcCodeLevel the client side errors are warn, the server side errors are error
+func grpcCodeLevel(code codes.Code) golog.Level {
+	switch code {
+	case codes.OK:
+		return golog.InfoLevel
+	case codes.Canceled, codes.InvalidArgument, codes.NotFound, codes.AlreadyExists,
+		codes.PermissionDenied, codes.Unauthenticated, codes.ResourceExhausted,
+		codes.FailedPrecondition, codes.Aborted, codes.OutOfRange:
+		return golog.WarnLevel
+	default:
+		return golog.ErrorLevel
+	}
+}
+
+func logCall(ctx context.Context, l golog.LoggerInterface, kind, method string, start time.Time, size int, err error) {
+	code := status.Code(err)
+	fields := map[string]interface{}{
+		"grpc.kind":         kind,
+		"grpc.method":       method,
+		"grpc.code":         code.String(),
+		"grpc.duration":     time.Since(start),
+		"grpc.request_size": size,
+	}
+
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		fields["peer.address"] = p.Addr.String()
+	}
+
+	if err != nil {
+		fields["error"] = err.Error()
+	}
+
+	switch grpcCodeLevel(code) {
+	case golog.InfoLevel:
+		l.InfoContextWithFields(ctx, fields, "finished %s call", kind)
+	case golog.WarnLevel:
+		l.WarnContextWithFields(ctx, fields, "finished %s call", kind)
+	default:
+		l.ErrorContextWithFields(ctx, fields, "finished %s call", kind)
+	}
+}
+
+func protoSize(msg interface{}) int {
+	if m, ok := msg.(proto.Message); ok {
+		return proto.Size(m)
+	}
+	return 0
+}
+
+// UnaryServerInterceptor log every unary call with method, peer, code, duration and request size
+func UnaryServerInterceptor(l golog.LoggerInterface) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		logCall(ctx, l, "server unary", info.FullMethod, start, protoSize(req), err)
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor log every stream call, the request size is the total of the received messages
+func StreamServerInterceptor(l golog.LoggerInterface) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		s := &sizeServerStream{ServerStream: ss}
+		err := handler(srv, s)
+		logCall(ss.Context(), l, "server stream", info.FullMethod, start, s.size, err)
+		return err
+	}
+}
+
+type sizeServerStream struct {
+	grpc.ServerStream
+	size int
+}
+
+func (s *sizeServerStream) RecvMsg(m interface{}) error {
+	err := s.ServerStream.RecvMsg(m)
+	if err == nil {
+		s.size += protoSize(m)
+	}
+	return err
+}
+
+// UnaryClientInterceptor log every unary call the client made
+func UnaryClientInterceptor(l golog.LoggerInterface) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		start := time.Now()
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		logCall(ctx, l, "client unary", method, start, protoSize(req), err)
+		return err
+	}
+}
+
+// StreamClientInterceptor log every stream the client made when it end,
+// the request size is the total of the sent messages
+func StreamClientInterceptor(l golog.LoggerInterface) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		start := time.Now()
+		cs, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			logCall(ctx, l, "client stream", method, start, 0, err)
+			return cs, err
+		}
+
+		return &sizeClientStream{ClientStream: cs, desc: desc, ctx: ctx, l: l, method: method, start: start}, nil
+	}
+}
+
+type sizeClientStream struct {
+	grpc.ClientStream
+	desc   *grpc.StreamDesc
+	ctx    context.Context
+	l      golog.LoggerInterface
+	method string
+	start  time.Time
+
+	size int
+	once sync.Once
+}
+
+func (s *sizeClientStream) SendMsg(m interface{}) error {
+	err := s.ClientStream.SendMsg(m)
+	if err == nil {
+		s.size += protoSize(m)
+	}
+	return err
+}
+
+// RecvMsg the stream end when it return error, io.EOF means ok,
+// or after the only response received when the server does not stream
+func (s *sizeClientStream) RecvMsg(m interface{}) error {
+	err := s.ClientStream.RecvMsg(m)
+	if err != nil || !s.desc.ServerStreams {
+		s.once.Do(func() {
+			logErr := err
+			if logErr == io.EOF {
+				logErr = nil
+			}
+			logCall(s.ctx, s.l, "client stream", s.method, s.start, s.size, logErr)
+		})
+	}
+	return err
+}
+
+// logPanic same as the one used by golog.Recover
+func logPanic(ctx context.Context, l golog.LoggerInterface, r interface{}, stack []byte) {
+	fields := map[string]interface{}{
+		"panic": fmt.Sprint(r),
+		"stack": string(stack),
+	}
+	l.ErrorContextWithFields(ctx, fields, "recovered from panic: %v", r)
+}
+
+// UnaryServerRecoveryInterceptor recover the panic of the handler, log it and return codes.Internal,
+// the panic value is only logged, the client get "internal error"
+func UnaryServerRecoveryInterceptor(l golog.LoggerInterface) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				logPanic(ctx, l, r, debug.Stack())
+				err = status.Error(codes.Internal, "internal error")
+			}
+		}()
+
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerRecoveryInterceptor same as UnaryServerRecoveryInterceptor for the stream handler
+func StreamServerRecoveryInterceptor(l golog.LoggerInterface) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				logPanic(ss.Context(), l, r, debug.Stack())
+				err = status.Error(codes.Internal, "internal error")
+			}
+		}()
+
+		return handler(srv, ss)
+	}
+}
@@ -0,0 +1,236 @@
+package grpclog
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/hunterhug/golog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	grpcLog "google.golang.org/grpc/grpclog"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	testpb "google.golang.org/grpc/interop/grpc_testing"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestGRPCInterceptors(t *testing.T) {
+	dir := t.TempDir()
+	l := golog.New().SetOutputFile(dir, "grpc").SetOutputJson(true)
+	l.SetFieldFunc("diy", func(ctx context.Context, m map[string]interface{}) {
+		if v := ctx.Value("diy"); v != nil {
+			m["diy"] = v
+		}
+	})
+	l.InitLogger()
+
+	srv, conn := startGRPC(t, l, func(srv *grpc.Server) {
+		healthpb.RegisterHealthServer(srv, health.NewServer())
+	})
+
+	client := healthpb.NewHealthClient(conn)
+	ctx := context.WithValue(context.Background(), "diy", "d")
+	if _, err := client.Check(ctx, &healthpb.HealthCheckRequest{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.Check(ctx, &healthpb.HealthCheckRequest{Service: "nope"}); err == nil {
+		t.Fatal("want NotFound")
+	}
+
+	watchCtx, cancel := context.WithCancel(ctx)
+	stream, err := client.Watch(watchCtx, &healthpb.HealthCheckRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := stream.Recv(); err != nil {
+		t.Fatal(err)
+	}
+	cancel()
+	for err == nil {
+		_, err = stream.Recv()
+	}
+
+	srv.GracefulStop()
+
+	b, err := ioutil.ReadFile(filepath.Join(dir, "grpc_info.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := string(b)
+	for _, want := range []string{
+		`"msg":"finished server unary call"`,
+		`"msg":"finished client unary call"`,
+		`"msg":"finished client stream call"`,
+		`"msg":"finished server stream call"`,
+		`"grpc.method":"/grpc.health.v1.Health/Check"`,
+		`"grpc.code":"NotFound"`,
+		`"grpc.code":"Canceled"`,
+		`"peer.address":"bufconn"`,
+		`"diy":"d"`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("%s should be in %s", want, out)
+		}
+	}
+}
+
+// startGRPC serve by bufconn with all the interceptors, the server and the conn are stopped by cleanup
+func startGRPC(t *testing.T, l golog.LoggerInterface, register func(*grpc.Server)) (*grpc.Server, *grpc.ClientConn) {
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer(
+		grpc.UnaryInterceptor(UnaryServerInterceptor(l)),
+		grpc.StreamInterceptor(StreamServerInterceptor(l)),
+	)
+	register(srv)
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(UnaryClientInterceptor(l)),
+		grpc.WithStreamInterceptor(StreamClientInterceptor(l)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return srv, conn
+}
+
+type inputServer struct {
+	testpb.UnimplementedTestServiceServer
+}
+
+func (inputServer) StreamingInputCall(stream testpb.TestService_StreamingInputCallServer) error {
+	size := 0
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			return stream.SendAndClose(&testpb.StreamingInputCallResponse{AggregatedPayloadSize: int32(size)})
+		}
+		if err != nil {
+			return err
+		}
+		size += len(req.GetPayload().GetBody())
+	}
+}
+
+func TestGRPCClientStreaming(t *testing.T) {
+	dir := t.TempDir()
+	l := golog.New().SetOutputFile(dir, "grpcin").SetOutputJson(true)
+	l.InitLogger()
+
+	srv, conn := startGRPC(t, l, func(srv *grpc.Server) {
+		testpb.RegisterTestServiceServer(srv, inputServer{})
+	})
+
+	stream, err := testpb.NewTestServiceClient(conn).StreamingInputCall(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := stream.Send(&testpb.StreamingInputCallRequest{Payload: &testpb.Payload{Body: []byte("abc")}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	resp, err := stream.CloseAndRecv()
+	if err != nil || resp.GetAggregatedPayloadSize() != 6 {
+		t.Fatalf("unexpected response %v %v", resp, err)
+	}
+	srv.GracefulStop()
+
+	b, err := ioutil.ReadFile(filepath.Join(dir, "grpcin_info.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var found bool
+	for _, line := range strings.Split(string(b), "\n") {
+		if strings.Contains(line, `"msg":"finished client stream call"`) {
+			found = strings.Contains(line, `"grpc.method":"/grpc.testing.TestService/StreamingInputCall"`) &&
+				strings.Contains(line, `"grpc.code":"OK"`)
+		}
+	}
+
+	if !found {
+		t.Fatalf("the client stream should be logged: %s", b)
+	}
+}
+
+// nextCaller return the console caller and func of the line after the call, the logged caller should be it
+func nextCaller() string {
+	pc, file, line, _ := runtime.Caller(1)
+	return fmt.Sprintf("%s:%d\t%s", filepath.Base(file), line+1, runtime.FuncForPC(pc).Name())
+}
+
+func TestGRPCLogger(t *testing.T) {
+	dir := t.TempDir()
+	l := golog.New().SetOutputFile(dir, "grpclog").SetCallerShort(true)
+	l.InitLogger()
+
+	grpcLog.SetLoggerV2(NewLogger(l))
+	t.Cleanup(func() {
+		grpcLog.SetLoggerV2(grpcLog.NewLoggerV2(io.Discard, os.Stderr, os.Stderr))
+	})
+
+	infoCaller := nextCaller()
+	grpcLog.Infoln("a", "b")
+	warnCaller := nextCaller()
+	grpcLog.Warningf("w %d", 1)
+	componentCaller := nextCaller()
+	grpcLog.Component("comp").Infof("c %d", 2)
+	if grpcLog.V(2) {
+		t.Fatal("V(2) should be false at info level")
+	}
+
+	b, err := ioutil.ReadFile(filepath.Join(dir, "grpclog_info.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(string(b), infoCaller+"\ta b") ||
+		!strings.Contains(string(b), warnCaller+"\tw 1") ||
+		!strings.Contains(string(b), componentCaller+"\t[comp] c 2") {
+		t.Fatalf("unexpected output: %s", b)
+	}
+}
+
+func TestRecoveryInterceptor(t *testing.T) {
+	dir := t.TempDir()
+	l := golog.New().SetOutputFile(dir, "recover").SetOutputJson(true)
+	l.InitLogger()
+
+	_, err := UnaryServerRecoveryInterceptor(l)(context.Background(), nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, req interface{}) (interface{}, error) {
+		panic("grpc crashed")
+	})
+	if status.Code(err) != codes.Internal || strings.Contains(err.Error(), "grpc crashed") {
+		t.Fatalf("want Internal without the panic value, got %v", err)
+	}
+
+	b, err := ioutil.ReadFile(filepath.Join(dir, "recover_err.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(string(b), `"panic":"grpc crashed"`) || !strings.Contains(string(b), `"stack":"goroutine `) {
+		t.Errorf("the panic should be logged: %s", b)
+	}
+}
@@ -0,0 +1,344 @@
+package golog
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LevelHandler return a http handler to view and change the level of the logger,
+// GET report the current level and name, PUT or POST change it, the body can be json or form:
+//
+//	{"level": "debug", "expire": "5m"}
+//	level=debug&expire=5m
+//
+// when expire set the level will revert automatically after that duration
+func LevelHandler(l LoggerInterface) http.Handler {
+	return &levelHandler{l: l}
+}
+
+type levelHandler struct {
+	l LoggerInterface
+
+	mu sync.Mutex
+	// timer revert the level when expire, nil when no revert pending
+	timer    *time.Timer
+	revertTo Level
+	expireAt time.Time
+}
+
+type levelRequest struct {
+	Level  string `json:"level"`
+	Expire string `json:"expire"`
+}
+
+type levelResponse struct {
+	Name     string     `json:"name"`
+	Level    string     `json:"level"`
+	ExpireAt *time.Time `json:"expire_at,omitempty"`
+	Error    string     `json:"error,omitempty"`
+}
+
+func (h *levelHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.reply(w, http.StatusOK, "")
+	case http.MethodPut, http.MethodPost:
+		req, err := decodeLevelRequest(r)
+		if err != nil {
+			h.reply(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		level, err := ParseLevel(req.Level)
+		if err != nil {
+			h.reply(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		var expire time.Duration
+		if req.Expire != "" {
+			expire, err = time.ParseDuration(req.Expire)
+			if err != nil || expire <= 0 {
+				h.reply(w, http.StatusBadRequest, fmt.Sprintf("invalid expire %q", req.Expire))
+				return
+			}
+		}
+
+		h.setLevel(level, expire)
+		h.reply(w, http.StatusOK, "")
+	default:
+		w.Header().Set("Allow", "GET, PUT, POST")
+		h.reply(w, http.StatusMethodNotAllowed, "only GET, PUT and POST are supported")
+	}
+}
+
+func decodeLevelRequest(r *http.Request) (req levelRequest, err error) {
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+		if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+			return req, fmt.Errorf("invalid json body: %s", err)
+		}
+
+		return req, nil
+	}
+
+	if err = r.ParseForm(); err != nil {
+		return req, err
+	}
+
+	req.Level = r.Form.Get("level")
+	req.Expire = r.Form.Get("expire")
+	return req, nil
+}
+
+// setLevel change the level, a new change cancel the pending revert but keep the level to revert to
+func (h *levelHandler) setLevel(level Level, expire time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	revertTo := h.l.GetLevel()
+	if h.timer != nil {
+		h.timer.Stop()
+		revertTo = h.revertTo
+		h.timer = nil
+		h.expireAt = time.Time{}
+	}
+
+	h.l.SetLevel(level)
+	if expire <= 0 {
+		return
+	}
+
+	var timer *time.Timer
+	timer = time.AfterFunc(expire, func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+
+		// replaced by a later change
+		if h.timer != timer {
+			return
+		}
+
+		h.l.SetLevel(h.revertTo)
+		h.timer = nil
+		h.expireAt = time.Time{}
+	})
+
+	h.timer = timer
+	h.revertTo = revertTo
+	h.expireAt = time.Now().Add(expire)
+}
+
+func (h *levelHandler) reply(w http.ResponseWriter, code int, errMsg string) {
+	h.mu.Lock()
+	resp := levelResponse{
+		Name:  h.l.GetName(),
+		Level: h.l.GetLevel().String(),
+		Error: errMsg,
+	}
+	if !h.expireAt.IsZero() {
+		expireAt := h.expireAt
+		resp.ExpireAt = &expireAt
+	}
+	h.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// HTTPOption config HTTPMiddleware
+type HTTPOption func(*httpOptions)
+
+type httpOptions struct {
+	requestIDHeader string
+	debugHeader     string
+	accessLog       bool
+	omit            map[string]bool
+	extraFields     func(r *http.Request) map[string]interface{}
+}
+
+// WithRequestIDHeader set the header the request id read from, default X-Request-Id,
+// a new id is generated and set to the response when the request has none
+func WithRequestIDHeader(header string) HTTPOption {
+	return func(o *httpOptions) {
+		o.requestIDHeader = header
+	}
+}
+
+// WithDebugHeader mark the request context by ForceDebug when the header is 1, true, on or yes,
+// so the debug logs of this request are written even the level is above debug
+func WithDebugHeader(header string) HTTPOption {
+	return func(o *httpOptions) {
+		o.debugHeader = header
+	}
+}
+
+// WithAccessLog write by the logger named AccessLoggerName, it goes to access.log when SetAccessLog(true)
+func WithAccessLog() HTTPOption {
+	return func(o *httpOptions) {
+		o.accessLog = true
+	}
+}
+
+// WithoutHTTPFields drop the default fields, eg: http.user_agent
+func WithoutHTTPFields(names ...string) HTTPOption {
+	return func(o *httpOptions) {
+		for _, name := range names {
+			o.omit[name] = true
+		}
+	}
+}
+
+// WithHTTPFields add more fields of the request
+func WithHTTPFields(f func(r *http.Request) map[string]interface{}) HTTPOption {
+	return func(o *httpOptions) {
+		o.extraFields = f
+	}
+}
+
+// HTTPMiddleware write one line for every request by InfoContextWithFields, 4xx is warn and 5xx is error,
+// fields: http.method, http.path, http.status, http.bytes, http.latency, http.remote_addr,
+// http.user_agent and http.request_id, the handler can get a child of l with http.request_id by FromContext
+func HTTPMiddleware(l LoggerInterface, opts ...HTTPOption) func(http.Handler) http.Handler {
+	o := &httpOptions{requestIDHeader: "X-Request-Id", omit: make(map[string]bool)}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	// the logger put into the request context for the handlers
+	base := l
+	if o.accessLog {
+		l = l.Named(AccessLoggerName)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+
+			requestID := r.Header.Get(o.requestIDHeader)
+			if requestID == "" {
+				requestID = newRequestID()
+				w.Header().Set(o.requestIDHeader, requestID)
+			}
+
+			ctx := NewContext(r.Context(), base.With(map[string]interface{}{"http.request_id": requestID}))
+			if o.debugHeader != "" {
+				switch strings.ToLower(r.Header.Get(o.debugHeader)) {
+				case "1", "true", "on", "yes":
+					ctx = ForceDebug(ctx)
+				}
+			}
+			r = r.WithContext(ctx)
+
+			rw := &responseWriter{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(rw, r)
+
+			fields := map[string]interface{}{
+				"http.method":      r.Method,
+				"http.path":        r.URL.Path,
+				"http.status":      rw.status,
+				"http.bytes":       rw.bytes,
+				"http.latency":     time.Since(start),
+				"http.remote_addr": r.RemoteAddr,
+				"http.user_agent":  r.UserAgent(),
+				"http.request_id":  requestID,
+			}
+			for name := range o.omit {
+				delete(fields, name)
+			}
+
+			if o.extraFields != nil {
+				for k, v := range o.extraFields(r) {
+					fields[k] = v
+				}
+			}
+
+			switch {
+			case rw.status >= 500:
+				l.ErrorContextWithFields(r.Context(), fields, "%s %s", r.Method, r.URL.Path)
+			case rw.status >= 400:
+				l.WarnContextWithFields(r.Context(), fields, "%s %s", r.Method, r.URL.Path)
+			default:
+				l.InfoContextWithFields(r.Context(), fields, "%s %s", r.Method, r.URL.Path)
+			}
+		})
+	}
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// responseWriter record the status and the bytes written
+type responseWriter struct {
+	http.ResponseWriter
+	status      int
+	bytes       int
+	wroteHeader bool
+}
+
+func (w *responseWriter) WriteHeader(code int) {
+	if !w.wroteHeader {
+		w.status = code
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *responseWriter) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+	n, err := w.ResponseWriter.Write(b)
+	w.bytes += n
+	return n, err
+}
+
+// ReadFrom let the io.Copy to the response use sendfile when the inner writer can
+func (w *responseWriter) ReadFrom(r io.Reader) (int64, error) {
+	w.wroteHeader = true
+	var n int64
+	var err error
+	if rf, ok := w.ResponseWriter.(io.ReaderFrom); ok {
+		n, err = rf.ReadFrom(r)
+	} else {
+		n, err = io.Copy(w.ResponseWriter, r)
+	}
+	w.bytes += int(n)
+	return n, err
+}
+
+// Hijack used by the websocket upgrade, the status is logged as 101 after hijacked
+func (w *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("golog: %T is not a http.Hijacker", w.ResponseWriter)
+	}
+
+	conn, rw, err := h.Hijack()
+	if err == nil && !w.wroteHeader {
+		w.status = http.StatusSwitchingProtocols
+		w.wroteHeader = true
+	}
+	return conn, rw, err
+}
+
+func (w *responseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap used by http.ResponseController
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,188 @@
+package golog
+
+import (
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLevelHandler(t *testing.T) {
+	l := New().SetName("level_handler")
+	l.InitLogger()
+	h := LevelHandler(l)
+
+	do := func(method, contentType, body string) (int, levelResponse) {
+		req := httptest.NewRequest(method, "/log/level", strings.NewReader(body))
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+
+		var resp levelResponse
+		if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+			t.Fatal(err)
+		}
+		return w.Code, resp
+	}
+
+	code, resp := do(http.MethodGet, "", "")
+	if code != http.StatusOK || resp.Level != "info" || resp.Name != "level_handler" {
+		t.Fatalf("unexpected get: %d %+v", code, resp)
+	}
+
+	code, resp = do(http.MethodPut, "application/json", `{"level":"debug"}`)
+	if code != http.StatusOK || resp.Level != "debug" || l.GetLevel() != DebugLevel {
+		t.Fatalf("unexpected put: %d %+v", code, resp)
+	}
+
+	code, _ = do(http.MethodPut, "application/json", `{"level":"verbose"}`)
+	if code != http.StatusBadRequest {
+		t.Fatalf("want 400, got %d", code)
+	}
+
+	form := url.Values{"level": {"warn"}, "expire": {"50ms"}}.Encode()
+	code, resp = do(http.MethodPost, "application/x-www-form-urlencoded", form)
+	if code != http.StatusOK || resp.Level != "warn" || resp.ExpireAt == nil {
+		t.Fatalf("unexpected post: %d %+v", code, resp)
+	}
+
+	time.Sleep(200 * time.Millisecond)
+	if l.GetLevel() != DebugLevel {
+		t.Fatalf("want revert to debug, got %s", l.GetLevel())
+	}
+
+	code, _ = do(http.MethodDelete, "", "")
+	if code != http.StatusMethodNotAllowed {
+		t.Fatalf("want 405, got %d", code)
+	}
+}
+
+func TestHTTPMiddleware(t *testing.T) {
+	dir := t.TempDir()
+	l := New().SetOutputFile(dir, "http").SetOutputJson(true).SetAccessLog(true)
+	l.InitLogger()
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/missing":
+			w.WriteHeader(http.StatusNotFound)
+		case "/broken":
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		_, _ = w.Write([]byte("hello"))
+	})
+
+	access := HTTPMiddleware(l, WithAccessLog(), WithoutHTTPFields("http.user_agent"))(handler)
+	plain := HTTPMiddleware(l, WithHTTPFields(func(r *http.Request) map[string]interface{} {
+		return map[string]interface{}{"tenant": r.Header.Get("X-Tenant")}
+	}))(handler)
+
+	req := httptest.NewRequest(http.MethodGet, "/ok", nil)
+	req.Header.Set("X-Request-Id", "rid")
+	access.ServeHTTP(httptest.NewRecorder(), req)
+	access.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/missing", nil))
+
+	req = httptest.NewRequest(http.MethodPost, "/broken", nil)
+	req.Header.Set("X-Tenant", "t1")
+	w := httptest.NewRecorder()
+	plain.ServeHTTP(w, req)
+	if w.Header().Get("X-Request-Id") == "" {
+		t.Fatal("request id should be generated")
+	}
+
+	read := func(name string) string {
+		b, err := ioutil.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(b)
+	}
+
+	out := read("http_access.log")
+	for _, want := range []string{`"msg":"GET /ok"`, `"http.status":200`, `"http.bytes":5`, `"http.request_id":"rid"`, `"l":"warn"`, `"http.status":404`} {
+		if !strings.Contains(out, want) {
+			t.Errorf("%s should be in %s", want, out)
+		}
+	}
+
+	if strings.Contains(out, "http.user_agent") || strings.Contains(out, "/broken") {
+		t.Errorf("unexpected access log: %s", out)
+	}
+
+	out = read("http_err.log")
+	if !strings.Contains(out, `"msg":"POST /broken"`) || !strings.Contains(out, `"tenant":"t1"`) || strings.Contains(out, "/ok") {
+		t.Errorf("unexpected error log: %s", out)
+	}
+}
+
+func TestHTTPMiddlewareHijack(t *testing.T) {
+	dir := t.TempDir()
+	l := New().SetOutputFile(dir, "hijack").SetOutputJson(true).SetAccessLog(true)
+	l.InitLogger()
+
+	srv := httptest.NewServer(HTTPMiddleware(l, WithAccessLog())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/copy" {
+			if _, ok := w.(io.ReaderFrom); !ok {
+				t.Error("the writer should be a io.ReaderFrom")
+			}
+			_, _ = io.Copy(w, strings.NewReader("copied"))
+			return
+		}
+
+		h, ok := w.(http.Hijacker)
+		if !ok {
+			t.Error("the writer should be a http.Hijacker")
+			return
+		}
+
+		conn, rw, err := h.Hijack()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer conn.Close()
+
+		_, _ = rw.WriteString("HTTP/1.1 101 Switching Protocols\r\nUpgrade: test\r\nConnection: Upgrade\r\n\r\n")
+		_ = rw.Flush()
+	})))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/copy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, srv.URL+"/upgrade", nil)
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "test")
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("want 101, got %d", resp.StatusCode)
+	}
+
+	b, err := ioutil.ReadFile(filepath.Join(dir, "hijack_access.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{`"msg":"GET /copy","http.bytes":6`, `"msg":"GET /upgrade"`, `"http.status":101`} {
+		if !strings.Contains(string(b), want) {
+			t.Errorf("%s should be in %s", want, b)
+		}
+	}
+}
@@ -0,0 +1,290 @@
+package golog
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// LevelSpecEnv is the environment read by New, eg: GOLOG_LEVEL=info,billing=debug,billing.sql=warn
+const LevelSpecEnv = "GOLOG_LEVEL"
+
+// levelTable hold the level of each logger name, name is dot separated and the longest prefix win
+type levelTable struct {
+	mu     sync.RWMutex
+	levels map[string]Level
+	// min is the lowest level in levels, used to answer Enabled without a name
+	min Level
+}
+
+func newLevelTable() *levelTable {
+	return &levelTable{levels: make(map[string]Level)}
+}
+
+func (t *levelTable) set(name string, level Level) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.levels[name] = level
+	t.resetMin()
+}
+
+func (t *levelTable) remove(name string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.levels, name)
+	t.resetMin()
+}
+
+func (t *levelTable) replace(levels map[string]Level) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.levels = levels
+	t.resetMin()
+}
+
+func (t *levelTable) all() map[string]Level {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	levels := make(map[string]Level, len(t.levels))
+	for k, v := range t.levels {
+		levels[k] = v
+	}
+	return levels
+}
+
+// must be locked
+func (t *levelTable) resetMin() {
+	t.min = FatalLevel
+	for _, v := range t.levels {
+		if v < t.min {
+			t.min = v
+		}
+	}
+}
+
+// minLevel return the lowest level of all names, false when no name set
+func (t *levelTable) minLevel() (Level, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.min, len(t.levels) > 0
+}
+
+// find the level of name, try billing.sql.read then billing.sql then billing
+func (t *levelTable) find(name string) (Level, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if len(t.levels) == 0 || name == "" {
+		return 0, false
+	}
+
+	for {
+		if level, ok := t.levels[name]; ok {
+			return level, true
+		}
+
+		i := strings.LastIndexByte(name, '.')
+		if i < 0 {
+			return 0, false
+		}
+		name = name[:i]
+	}
+}
+
+// nameLevel find the level of the logger name, the global level when no name level set
+type nameLevel struct {
+	level  zap.AtomicLevel
+	table  *levelTable
+	prefix string
+}
+
+func newNameLevel(c config) nameLevel {
+	prefix := ""
+	if c.name != "" {
+		prefix = c.name + "."
+	}
+
+	return nameLevel{level: c.level, table: c.levels, prefix: prefix}
+}
+
+// of try the full name first, then the name without the root name set by SetName
+func (n nameLevel) of(name string) Level {
+	if level, ok := n.table.find(name); ok {
+		return level
+	}
+
+	if n.prefix != "" && strings.HasPrefix(name, n.prefix) {
+		if level, ok := n.table.find(name[len(n.prefix):]); ok {
+			return level
+		}
+	}
+
+	return n.level.Level()
+}
+
+// levelCore decide whether the entry can be written by the logger name,
+// the cores inside only care about which file the level goes to
+type levelCore struct {
+	zapcore.Core
+	nameLevel
+	// force ignore the level, see ForceDebug
+	force bool
+}
+
+func newLevelCore(core zapcore.Core, c config) zapcore.Core {
+	return &levelCore{Core: core, nameLevel: newNameLevel(c)}
+}
+
+func (c *levelCore) Enabled(lvl zapcore.Level) bool {
+	if c.force || c.level.Enabled(lvl) {
+		return true
+	}
+
+	min, ok := c.table.minLevel()
+	return ok && lvl >= min
+}
+
+func (c *levelCore) With(fields []zapcore.Field) zapcore.Core {
+	return &levelCore{Core: c.Core.With(fields), nameLevel: c.nameLevel, force: c.force}
+}
+
+func (c *levelCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.force && !c.of(ent.LoggerName).Enabled(ent.Level) {
+		return ce
+	}
+
+	return c.Core.Check(ent, ce)
+}
+
+// forceCore return the core ignore the level, the cores inside still decide which file to write,
+// the buffer of NewBuffered is skipped, the forced entries are written at once
+func forceCore(core zapcore.Core) zapcore.Core {
+	switch c := core.(type) {
+	case *bufferCore:
+		return forceCore(c.Core)
+	case *levelCore:
+		return &levelCore{Core: c.Core, nameLevel: c.nameLevel, force: true}
+	default:
+		return core
+	}
+}
+
+// fileCore write a file only when the level of the logger name is at or below the lowest level of the file,
+// eg: at warn level the debug and info files get nothing, the warn and error entries go to the warn and error files,
+// the entries below the level are forced ones, see ForceDebug, they go to the file as its levels allow
+type fileCore struct {
+	zapcore.Core
+	nameLevel
+	min Level
+}
+
+func (c *fileCore) With(fields []zapcore.Field) zapcore.Core {
+	return &fileCore{Core: c.Core.With(fields), nameLevel: c.nameLevel, min: c.min}
+}
+
+func (c *fileCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if level := c.of(ent.LoggerName); ent.Level >= level && level > c.min {
+		return ce
+	}
+
+	return c.Core.Check(ent, ce)
+}
+
+// parseLevelSpec parse spec like "info,billing=debug,billing.sql=warn",
+// the item without name is the default level, hasLevel is false when not set
+func parseLevelSpec(spec string) (level Level, hasLevel bool, levels map[string]Level, err error) {
+	levels = make(map[string]Level)
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		i := strings.IndexByte(item, '=')
+		if i < 0 {
+			level, err = ParseLevel(item)
+			if err != nil {
+				return level, false, nil, err
+			}
+			hasLevel = true
+			continue
+		}
+
+		name := strings.TrimSpace(item[:i])
+		if name == "" {
+			return level, false, nil, fmt.Errorf("empty logger name in %q", item)
+		}
+
+		l, err := ParseLevel(strings.TrimSpace(item[i+1:]))
+		if err != nil {
+			return level, false, nil, err
+		}
+		levels[name] = l
+	}
+
+	return level, hasLevel, levels, nil
+}
+
+// levelSpec format the level and the name levels back to spec
+func levelSpec(level Level, levels map[string]Level) string {
+	items := make([]string, 0, len(levels)+1)
+	items = append(items, level.String())
+
+	names := make([]string, 0, len(levels))
+	for k := range levels {
+		names = append(names, k)
+	}
+
+	sort.Strings(names)
+	for _, name := range names {
+		items = append(items, name+"="+levels[name].String())
+	}
+	return strings.Join(items, ",")
+}
+
+// AccessLoggerName is the name of the access logger, see SetAccessLog
+const AccessLoggerName = "access"
+
+// accessCore send the entries of the access logger to the access cores, others to the level cores
+type accessCore struct {
+	zapcore.Core
+	access zapcore.Core
+}
+
+func newAccessCore(core, access zapcore.Core) zapcore.Core {
+	return &accessCore{Core: core, access: access}
+}
+
+// isAccess the last part of the name is access, eg: svc.access
+func isAccess(name string) bool {
+	return name == AccessLoggerName || strings.HasSuffix(name, "."+AccessLoggerName)
+}
+
+func (c *accessCore) Enabled(lvl zapcore.Level) bool {
+	return c.Core.Enabled(lvl) || c.access.Enabled(lvl)
+}
+
+func (c *accessCore) With(fields []zapcore.Field) zapcore.Core {
+	return &accessCore{Core: c.Core.With(fields), access: c.access.With(fields)}
+}
+
+func (c *accessCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if isAccess(ent.LoggerName) {
+		return c.access.Check(ent, ce)
+	}
+	return c.Core.Check(ent, ce)
+}
+
+func (c *accessCore) Sync() error {
+	return multierr.Append(c.Core.Sync(), c.access.Sync())
+}
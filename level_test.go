@@ -0,0 +1,50 @@
+package golog
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLevelOverride(t *testing.T) {
+	dir := t.TempDir()
+	l := New().SetName("svc").SetOutputFile(dir, "override")
+	if err := l.SetLevelSpec("warn, billing=debug, billing.sql=error"); err != nil {
+		t.Fatal(err)
+	}
+	l.InitLogger()
+
+	if spec := l.GetLevelSpec(); spec != "warn,billing=debug,billing.sql=error" {
+		t.Fatalf("unexpected spec: %s", spec)
+	}
+
+	z := l.GetZapLogger()
+	z.Info("root info")
+	z.Named("billing").Debug("billing debug")
+	z.Named("billing").Named("sql").Warn("sql warn")
+	z.Named("billing").Named("sql").Error("sql error")
+	z.Named("other").Warn("other warn")
+
+	// each entry goes to the file of its level only when the level of its name is not above the file
+	for file, want := range map[string]string{
+		"override_debug.log": "billing debug",
+		"override_warn.log":  "other warn",
+		"override_err.log":   "sql error",
+	} {
+		b, err := ioutil.ReadFile(filepath.Join(dir, file))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, msg := range []string{"root info", "billing debug", "sql warn", "sql error", "other warn"} {
+			if strings.Contains(string(b), msg) != (msg == want) {
+				t.Errorf("%s want only %q: %s", file, want, b)
+			}
+		}
+	}
+
+	if _, _, _, err := parseLevelSpec("info,billing=verbose"); err == nil {
+		t.Fatal("want error for unknown level")
+	}
+}
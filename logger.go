@@ -2,6 +2,7 @@ package golog
 
 import (
 	"context"
+	"fmt"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 	"strings"
@@ -15,6 +16,8 @@ const (
 	InfoLevel  = zapcore.InfoLevel
 	WarnLevel  = zapcore.WarnLevel
 	ErrorLevel = zapcore.ErrorLevel
+	PanicLevel = zapcore.PanicLevel
+	FatalLevel = zapcore.FatalLevel
 )
 
 func StringLevel(level string) Level {
@@ -32,30 +35,90 @@ func StringLevel(level string) Level {
 	return zapcore.InfoLevel
 }
 
+// ParseLevel like StringLevel but return error when the level is unknown
+func ParseLevel(level string) (Level, error) {
+	switch strings.ToLower(level) {
+	case "debug":
+		return zapcore.DebugLevel, nil
+	case "info":
+		return zapcore.InfoLevel, nil
+	case "warn":
+		return zapcore.WarnLevel, nil
+	case "error":
+		return zapcore.ErrorLevel, nil
+	case "panic":
+		return zapcore.PanicLevel, nil
+	case "fatal":
+		return zapcore.FatalLevel, nil
+	}
+
+	return zapcore.InfoLevel, fmt.Errorf("unknown level %q", level)
+}
+
 // LoggerInterface hide something you can implement new one
 type LoggerInterface interface {
 	SetOutputFile(logPath, fileName string) LoggerInterface
 	SetFileRotate(fileMaxAge, fileRotation time.Duration) LoggerInterface
+	// SetFileMaxSize rotate the file by size too, whichever first
+	SetFileMaxSize(maxSize int64) LoggerInterface
+	// SetFileMaxBackups keep at most the number of rotated files for each file
+	SetFileMaxBackups(maxBackups int) LoggerInterface
+	// SetLogPathMaxSize the total size of the log path, the oldest rotated files removed when exceeded
+	SetLogPathMaxSize(maxSize int64) LoggerInterface
+	// SetFileCompress compress the rotated files by CompressGzip or the one added by RegisterCompressor
+	SetFileCompress(compress string) LoggerInterface
+	// SetOutputs route the entries to the files by level instead of the default four files
+	SetOutputs(outputs ...Output) LoggerInterface
+	// SetOutputSingleFile write all the entries to one file instead of the default four files
+	SetOutputSingleFile(single bool) LoggerInterface
 	SetLevel(level Level) LoggerInterface
+	// SetLevelOverride set the level of the named logger, the longest dot separated name win
+	SetLevelOverride(name string, level Level) LoggerInterface
+	RemoveLevelOverride(name string) LoggerInterface
+	// SetLevelSpec set level and overrides by spec like "info,billing=debug,billing.sql=warn"
+	SetLevelSpec(spec string) error
 	SetCallerShort(short bool) LoggerInterface
 	SetName(name string) LoggerInterface
 	SetIsOutputStdout(isOutputStdout bool) LoggerInterface
 	SetCallerSkip(skip int) LoggerInterface
 	SetOutputJson(json bool) LoggerInterface
+	// SetOutputColor color the level on stdout, ColorAuto color only when it is a terminal
+	SetOutputColor(color string) LoggerInterface
+	// SetStdoutEncoder and SetFileEncoder choose json or console for stdout and files separately
+	SetStdoutEncoder(encoder string) LoggerInterface
+	SetFileEncoder(encoder string) LoggerInterface
+	// SetAccessLog the logger named access only write the access.log
+	SetAccessLog(accessLog bool) LoggerInterface
 
 	GetOutputFile() (logPath, fileName string)
 	GetFileRotate() (fileMaxAge, fileRotation time.Duration)
+	GetFileMaxSize() (maxSize int64)
+	GetFileMaxBackups() (maxBackups int)
+	GetLogPathMaxSize() (maxSize int64)
+	GetFileCompress() (compress string)
+	GetOutputs() []Output
+	GetOutputSingleFile() (single bool)
 	GetLevel() (level Level)
+	GetLevelOverrides() map[string]Level
+	GetLevelSpec() string
 	GetCallerShort() (short bool)
 	GetName() (name string)
 	GetIsOutputStdout() (isOutputStdout bool)
 	GetCallerSkip() (skip int)
 	GetOutputJson() bool
+	GetOutputColor() (color string)
+	GetStdoutEncoder() (encoder string)
+	GetFileEncoder() (encoder string)
+	GetAccessLog() bool
 
 	// InitLogger init logger should call this when change config
 	InitLogger()
+	// InitLoggerE same as InitLogger but return error instead of panic, old logger keep working when fail
+	InitLoggerE() error
 	// Sync terminal the logger should call this to flush
 	Sync() error
+	// Reopen close all the files in use, the next write open them by name again
+	Reopen() error
 
 	Panicf(template string, args ...interface{})
 	Fatalf(template string, args ...interface{})
@@ -78,6 +141,14 @@ type LoggerInterface interface {
 	InfoWithFields(fields map[string]interface{}, template string, args ...interface{})
 	DebugWithFields(fields map[string]interface{}, template string, args ...interface{})
 
+	// PanicF typed fields, no reflection and keep the order
+	PanicF(msg string, fields ...Field)
+	FatalF(msg string, fields ...Field)
+	ErrorF(msg string, fields ...Field)
+	WarnF(msg string, fields ...Field)
+	InfoF(msg string, fields ...Field)
+	DebugF(msg string, fields ...Field)
+
 	PanicContext(ctx context.Context, template string, args ...interface{})
 	FatalContext(ctx context.Context, template string, args ...interface{})
 	ErrorContext(ctx context.Context, template string, args ...interface{})
@@ -92,8 +163,21 @@ type LoggerInterface interface {
 	InfoContextWithFields(ctx context.Context, fields map[string]interface{}, template string, args ...interface{})
 	DebugContextWithFields(ctx context.Context, fields map[string]interface{}, template string, args ...interface{})
 
-	// AddFieldFunc filter deal the fields
+	// With return a child logger with the fields bound, it shares the cores and level with the parent
+	With(fields map[string]interface{}) LoggerInterface
+	// Named return a child logger named parent.sub
+	Named(sub string) LoggerInterface
+	// WithCallerSkip return a child logger skip more callers, used when wrapped by an adapter
+	WithCallerSkip(skip int) LoggerInterface
+	// Enabled report whether level can be written, the level override of the name considered
+	Enabled(level Level) bool
+
+	// AddFieldFunc filter deal the fields, append to the field funcs which run by order,
+	// the key already in fields given by caller win the field funcs
 	AddFieldFunc(func(context.Context, map[string]interface{}))
+	// SetFieldFunc replace the field func with the same name, or append when not found
+	SetFieldFunc(name string, f FieldFunc) LoggerInterface
+	RemoveFieldFunc(name string) LoggerInterface
 
 	GetZapLogger() *zap.Logger
 	GetZapSugaredLogger() *zap.SugaredLogger
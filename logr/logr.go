@@ -0,0 +1,109 @@
+// Package logr adapt golog to logr.Logger, so the libraries log by logr, eg: controller-runtime,
+// write to the golog files with the logger names and the V-levels mapped to golog levels
+package logr
+
+import (
+	"fmt"
+
+	goLogr "github.com/go-logr/logr"
+	"github.com/hunterhug/golog"
+)
+
+// Option config the logr.Logger returned by New
+type Option func(*sink)
+
+// WithLevel set how the V-level map to golog level, default V(0) is info and V(1) and above is debug
+func WithLevel(f func(v int) golog.Level) Option {
+	return func(s *sink) {
+		s.levelFunc = f
+	}
+}
+
+func defaultLevel(v int) golog.Level {
+	if v <= 0 {
+		return golog.InfoLevel
+	}
+	return golog.DebugLevel
+}
+
+// New return a logr.Logger write to l, WithName act as Named and WithValues keep the values in order,
+// Error go to the error level
+func New(l golog.LoggerInterface, opts ...Option) goLogr.Logger {
+	s := &sink{l: l, levelFunc: defaultLevel}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return goLogr.New(s)
+}
+
+type sink struct {
+	l         golog.LoggerInterface
+	levelFunc func(v int) golog.Level
+	// values bound by WithValues
+	values []golog.Field
+}
+
+// Init fix the caller skip, logr.Logger wrap info.CallDepth layers outside the sink,
+// and the sink is 2 layers: Info or Error, then log
+func (s *sink) Init(info goLogr.RuntimeInfo) {
+	s.l = s.l.WithCallerSkip(info.CallDepth + 2)
+}
+
+func (s *sink) Enabled(level int) bool {
+	return s.l.Enabled(s.levelFunc(level))
+}
+
+func (s *sink) Info(level int, msg string, keysAndValues ...interface{}) {
+	s.log(s.levelFunc(level), msg, nil, keysAndValues)
+}
+
+func (s *sink) Error(err error, msg string, keysAndValues ...interface{}) {
+	s.log(golog.ErrorLevel, msg, err, keysAndValues)
+}
+
+func (s *sink) log(lvl golog.Level, msg string, err error, keysAndValues []interface{}) {
+	fields := make([]golog.Field, 0, len(s.values)+len(keysAndValues)/2+1)
+	fields = append(fields, s.values...)
+	fields = appendFields(fields, keysAndValues)
+	if err != nil {
+		fields = append(fields, golog.Err(err))
+	}
+
+	switch lvl {
+	case golog.DebugLevel:
+		s.l.DebugF(msg, fields...)
+	case golog.InfoLevel:
+		s.l.InfoF(msg, fields...)
+	case golog.WarnLevel:
+		s.l.WarnF(msg, fields...)
+	default:
+		s.l.ErrorF(msg, fields...)
+	}
+}
+
+// appendFields the last key without value get nil
+func appendFields(fields []golog.Field, keysAndValues []interface{}) []golog.Field {
+	for i := 0; i < len(keysAndValues); i += 2 {
+		key, ok := keysAndValues[i].(string)
+		if !ok {
+			key = fmt.Sprint(keysAndValues[i])
+		}
+
+		var value interface{}
+		if i+1 < len(keysAndValues) {
+			value = keysAndValues[i+1]
+		}
+		fields = append(fields, golog.Any(key, value))
+	}
+	return fields
+}
+
+func (s *sink) WithValues(keysAndValues ...interface{}) goLogr.LogSink {
+	values := make([]golog.Field, 0, len(s.values)+len(keysAndValues)/2)
+	values = append(values, s.values...)
+	return &sink{l: s.l, levelFunc: s.levelFunc, values: appendFields(values, keysAndValues)}
+}
+
+func (s *sink) WithName(name string) goLogr.LogSink {
+	return &sink{l: s.l.Named(name), levelFunc: s.levelFunc, values: s.values}
+}
@@ -0,0 +1,52 @@
+package logr
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/hunterhug/golog"
+)
+
+func TestLogr(t *testing.T) {
+	dir := t.TempDir()
+	l := golog.New().SetName("svc").SetOutputFile(dir, "logr").SetOutputJson(true).SetCallerShort(true)
+	l.InitLogger()
+
+	lr := New(l).WithName("controller").WithValues("b", 2, "a", 1)
+	_, file, line, _ := runtime.Caller(0)
+	lr.Info("reconcile", "obj", "x")
+	lr.V(1).Info("should not output")
+	lr.Error(errors.New("boom"), "failed")
+
+	if lr.V(1).Enabled() {
+		t.Fatal("V(1) should be disabled at info level")
+	}
+
+	l.SetLevelOverride("controller", golog.DebugLevel)
+	if !lr.V(1).Enabled() {
+		t.Fatal("V(1) should be enabled by the level override")
+	}
+
+	b, err := ioutil.ReadFile(filepath.Join(dir, "logr_info.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the caller is the line after runtime.Caller
+	caller := fmt.Sprintf(`/%s:%d","func":"github.com/hunterhug/golog/logr.TestLogr","msg":"reconcile"`, filepath.Base(file), line+1)
+	out := string(b)
+	for _, want := range []string{`"logger":"svc.controller"`, `"msg":"reconcile","b":2,"a":1,"obj":"x"`, `"l":"error"`, `"error":"boom"`, caller} {
+		if !strings.Contains(out, want) {
+			t.Errorf("%s should be in %s", want, out)
+		}
+	}
+
+	if strings.Contains(out, "should not output") {
+		t.Errorf("V(1) should not output: %s", out)
+	}
+}
@@ -0,0 +1,20 @@
+module github.com/hunterhug/golog/otel
+
+go 1.21
+
+require (
+	github.com/hunterhug/golog v0.0.0-20261017014531-c7e116b41996
+	go.opentelemetry.io/otel/trace v1.28.0
+)
+
+require (
+	github.com/lestrrat-go/file-rotatelogs v2.4.0+incompatible // indirect
+	github.com/lestrrat-go/strftime v1.0.5 // indirect
+	github.com/mattn/go-isatty v0.0.24 // indirect
+	github.com/pkg/errors v0.9.1 // indirect
+	go.opentelemetry.io/otel v1.28.0 // indirect
+	go.uber.org/atomic v1.9.0 // indirect
+	go.uber.org/multierr v1.7.0 // indirect
+	go.uber.org/zap v1.19.0 // indirect
+	golang.org/x/sys v0.28.0 // indirect
+)
@@ -0,0 +1,27 @@
+// Package otel add trace_id, span_id and trace_flags of the OpenTelemetry span in ctx to the
+// entries logged with a context, eg: InfoContext, so the logs can be joined with the traces
+package otel
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TraceFieldFuncName is the name of TraceFieldFunc, enable it by:
+//
+//	golog.SetFieldFunc(otel.TraceFieldFuncName, otel.TraceFieldFunc)
+const TraceFieldFuncName = "otel_trace"
+
+// TraceFieldFunc add trace_id, span_id and trace_flags of the OpenTelemetry span in ctx,
+// the keys follow the OpenTelemetry log data model, nothing added when no valid span
+func TraceFieldFunc(ctx context.Context, m map[string]interface{}) {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return
+	}
+
+	m["trace_id"] = sc.TraceID().String()
+	m["span_id"] = sc.SpanID().String()
+	m["trace_flags"] = sc.TraceFlags().String()
+}
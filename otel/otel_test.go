@@ -0,0 +1,45 @@
+package otel
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hunterhug/golog"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTraceFieldFunc(t *testing.T) {
+	m := make(map[string]interface{})
+	TraceFieldFunc(context.Background(), m)
+	if len(m) != 0 {
+		t.Fatalf("no span should add nothing: %v", m)
+	}
+
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x01, 0x02},
+		SpanID:     trace.SpanID{0x03},
+		TraceFlags: trace.FlagsSampled,
+	})
+	ctx := trace.ContextWithSpanContext(context.Background(), sc)
+
+	dir := t.TempDir()
+	l := golog.New().SetOutputFile(dir, "otel").SetOutputJson(true)
+	l.SetFieldFunc(TraceFieldFuncName, TraceFieldFunc)
+	l.InitLogger()
+	l.InfoContext(ctx, "traced")
+	l.Sync()
+
+	b, err := os.ReadFile(filepath.Join(dir, "otel_info.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{`"trace_id":"01020000000000000000000000000000"`, `"span_id":"0300000000000000"`, `"trace_flags":"01"`} {
+		if !strings.Contains(string(b), want) {
+			t.Errorf("%s should be in %s", want, b)
+		}
+	}
+}
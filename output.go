@@ -0,0 +1,142 @@
+package golog
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// EncoderJSON write the entries as json
+	EncoderJSON = "json"
+	// EncoderConsole write the entries as tab separated text
+	EncoderConsole = "console"
+
+	// OutputNameHolder in Output.Path is replaced by the fileName of SetOutputFile
+	OutputNameHolder = "{name}"
+)
+
+// Output is a file under the log path and the levels written to it, see SetOutputs
+type Output struct {
+	// Path the file name relative to the log path, eg: "{name}_err.log", rotated files are named after it
+	Path string `json:"path" yaml:"path"`
+	// Level the levels written, "warn" only warn, "warn+" warn and above, "debug-warn" debug to warn, empty all,
+	// the file get nothing when the level of the logger is above its lowest level, eg: "debug+" at info level
+	Level string `json:"level" yaml:"level"`
+	// Encoder EncoderJSON or EncoderConsole, empty follow SetFileEncoder
+	Encoder string `json:"encoder" yaml:"encoder"`
+}
+
+// defaultOutputs is the four files used when SetOutputs is not called, each one take its level and above,
+// the debug file without fileName was access.log, which is the access log of SetAccessLog now
+func defaultOutputs(fileName string) []Output {
+	if fileName == "" {
+		return []Output{
+			{Path: "debug.log", Level: "debug+"},
+			{Path: "info.log", Level: "info+"},
+			{Path: "warn.log", Level: "warn+"},
+			{Path: "error.log", Level: "error+"},
+		}
+	}
+
+	return []Output{
+		{Path: OutputNameHolder + "_debug.log", Level: "debug+"},
+		{Path: OutputNameHolder + "_info.log", Level: "info+"},
+		{Path: OutputNameHolder + "_warn.log", Level: "warn+"},
+		{Path: OutputNameHolder + "_err.log", Level: "error+"},
+	}
+}
+
+// singleOutputs is the one file used by SetOutputSingleFile
+func singleOutputs(fileName string) []Output {
+	if fileName == "" {
+		return []Output{{Path: "app.log"}}
+	}
+
+	return []Output{{Path: OutputNameHolder + ".log"}}
+}
+
+// fileOutputs the outputs used by the config
+func (c config) fileOutputs() []Output {
+	if c.singleFile {
+		return singleOutputs(c.fileName)
+	}
+
+	if len(c.outputs) > 0 {
+		return c.outputs
+	}
+
+	return defaultOutputs(c.fileName)
+}
+
+// fileName the full path of the output
+func (o Output) fileName(logPath, fileName string) string {
+	return filepath.Join(logPath, strings.ReplaceAll(o.Path, OutputNameHolder, fileName))
+}
+
+// leveled report whether the file follow the level of the logger, see fileCore, the file take all levels does not
+func (o Output) leveled() bool {
+	return strings.TrimSpace(o.Level) != ""
+}
+
+// levels parse the Level to the range [min, max]
+func (o Output) levels() (min, max Level, err error) {
+	spec := strings.TrimSpace(o.Level)
+	if spec == "" {
+		return DebugLevel, FatalLevel, nil
+	}
+
+	if strings.HasSuffix(spec, "+") {
+		min, err = ParseLevel(strings.TrimSpace(spec[:len(spec)-1]))
+		return min, FatalLevel, err
+	}
+
+	if i := strings.IndexByte(spec, '-'); i >= 0 {
+		if min, err = ParseLevel(strings.TrimSpace(spec[:i])); err != nil {
+			return min, max, err
+		}
+
+		if max, err = ParseLevel(strings.TrimSpace(spec[i+1:])); err != nil {
+			return min, max, err
+		}
+
+		if max < min {
+			return min, max, fmt.Errorf("level range %q is empty", spec)
+		}
+		return min, max, nil
+	}
+
+	min, err = ParseLevel(spec)
+	return min, min, err
+}
+
+// validEncoder report whether the encoder is known, empty is valid and follow the json config
+func validEncoder(encoder string) bool {
+	return encoder == "" || encoder == EncoderJSON || encoder == EncoderConsole
+}
+
+// validateOutputs add the problems of the outputs, two outputs can not write the same file
+func validateOutputs(problems *ConfigError, outputs []Output, logPath, fileName string) {
+	seen := make(map[string]bool, len(outputs))
+	for _, o := range outputs {
+		if strings.TrimSpace(o.Path) == "" {
+			problems.add("outputs.path", o.Path, errors.New("must not be empty"))
+			continue
+		}
+
+		name := o.fileName(logPath, fileName)
+		if seen[name] {
+			problems.add("outputs.path", o.Path, errors.New("duplicated"))
+		}
+		seen[name] = true
+
+		if _, _, err := o.levels(); err != nil {
+			problems.add("outputs.level", o.Level, err)
+		}
+
+		if !validEncoder(o.Encoder) {
+			problems.add("outputs.encoder", o.Encoder, errors.New("unknown encoder"))
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package golog
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestOutputs(t *testing.T) {
+	dir := t.TempDir()
+	l := New().SetOutputFile(dir, "route").SetLevel(DebugLevel).SetOutputs(
+		Output{Path: "{name}.log", Level: "info+", Encoder: EncoderJSON},
+		Output{Path: "{name}_debug.log", Level: "debug"},
+		Output{Path: "{name}_mid.log", Level: "info-warn", Encoder: EncoderConsole},
+	)
+	l.InitLogger()
+
+	l.Debug("debug line")
+	l.Info("info line")
+	l.Error("error line")
+
+	for file, want := range map[string][]string{
+		"route.log":       {`"msg":"info line"`, `"msg":"error line"`},
+		"route_debug.log": {"debug line"},
+		"route_mid.log":   {"info line"},
+	} {
+		b, err := os.ReadFile(filepath.Join(dir, file))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if lines := strings.Count(string(b), "\n"); lines != len(want) {
+			t.Errorf("%s want %d lines: %s", file, len(want), b)
+		}
+
+		for _, w := range want {
+			if !strings.Contains(string(b), w) {
+				t.Errorf("%s should be in %s", w, b)
+			}
+		}
+	}
+
+	err := New().SetOutputFile(dir, "bad").SetOutputs(
+		Output{Path: "a.log", Level: "warn-info"},
+		Output{Path: "a.log", Encoder: "xml"},
+	).InitLoggerE()
+
+	var configErr *ConfigError
+	if !errors.As(err, &configErr) || len(configErr.Problems) != 3 {
+		t.Fatalf("want 3 problems, got %v", err)
+	}
+}
+
+func TestOutputSingleFile(t *testing.T) {
+	dir := t.TempDir()
+	l := New().SetOutputFile(dir, "").SetOutputSingleFile(true)
+	l.InitLogger()
+
+	l.Debug("debug line")
+	l.Info("info line")
+	l.Error("error line")
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(files) != 2 || filepath.Base(files[0]) != "app.log" {
+		t.Fatalf("want app.log and the rotated file only, got %v", files)
+	}
+
+	b, err := os.ReadFile(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Contains(string(b), "debug line") || strings.Count(string(b), "\n") != 2 {
+		t.Errorf("want info and error line only: %s", b)
+	}
+}
+
+func TestSeparateEncoders(t *testing.T) {
+	dir := t.TempDir()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stdout := os.Stdout
+	os.Stdout = w
+	l := New().SetOutputFile(dir, "enc").SetIsOutputStdout(true).SetOutputColor(ColorNever).
+		SetStdoutEncoder(EncoderConsole).SetFileEncoder(EncoderJSON)
+	l.InitLogger()
+	os.Stdout = stdout
+
+	l.Info("separate")
+	w.Close()
+
+	out, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(string(out), "\tINFO\t") || strings.Contains(string(out), `"msg"`) {
+		t.Errorf("stdout should be console: %q", out)
+	}
+
+	b, err := os.ReadFile(filepath.Join(dir, "enc_info.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(string(b), `"msg":"separate"`) {
+		t.Errorf("file should be json: %q", b)
+	}
+
+	if err := New().SetStdoutEncoder("xml").InitLoggerE(); err == nil {
+		t.Error("unknown encoder should fail")
+	}
+}
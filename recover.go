@@ -0,0 +1,68 @@
+package golog
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"runtime/debug"
+)
+
+// Recover log the panic with the stack at error level by the logger in ctx, use it as:
+//
+//	defer golog.Recover(ctx)
+func Recover(ctx context.Context) {
+	if r := recover(); r != nil {
+		logPanic(ctx, FromContext(ctx), r, debug.Stack())
+	}
+}
+
+// RecoverWith same as Recover but write to l, and panic again with the same value when repanic
+func RecoverWith(ctx context.Context, l LoggerInterface, repanic bool) {
+	if r := recover(); r != nil {
+		logPanic(ctx, l, r, debug.Stack())
+		if repanic {
+			panic(r)
+		}
+	}
+}
+
+// logPanic the context fields come from the field funcs of l
+func logPanic(ctx context.Context, l LoggerInterface, r interface{}, stack []byte) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	fields := map[string]interface{}{
+		"panic": fmt.Sprint(r),
+		"stack": string(stack),
+	}
+	l.ErrorContextWithFields(ctx, fields, "recovered from panic: %v", r)
+}
+
+// HTTPRecover recover the panic of the handler, log it and reply 500, or panic again when repanic,
+// http.ErrAbortHandler always panic again since it is used to abort the response
+func HTTPRecover(l LoggerInterface, repanic bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer func() {
+				v := recover()
+				if v == nil {
+					return
+				}
+
+				if v == http.ErrAbortHandler {
+					panic(v)
+				}
+
+				logPanic(r.Context(), l, v, debug.Stack())
+				if repanic {
+					panic(v)
+				}
+
+				w.WriteHeader(http.StatusInternalServerError)
+			}()
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package golog
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRecover(t *testing.T) {
+	dir := t.TempDir()
+	l := New().SetOutputFile(dir, "recover").SetOutputJson(true)
+	l.SetFieldFunc("diy", func(ctx context.Context, m map[string]interface{}) {
+		if v := ctx.Value("diy"); v != nil {
+			m["diy"] = v
+		}
+	})
+	l.InitLogger()
+
+	ctx := context.WithValue(context.Background(), "diy", "d")
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		defer RecoverWith(ctx, l, false)
+		panic("worker crashed")
+	}()
+	<-done
+
+	func() {
+		defer func() {
+			if r := recover(); r != "again" {
+				t.Errorf("want panic again, got %v", r)
+			}
+		}()
+		defer RecoverWith(ctx, l, true)
+		panic("again")
+	}()
+
+	h := HTTPRecover(l, false)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("handler crashed")
+	}))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("want 500, got %d", w.Code)
+	}
+
+	b, err := ioutil.ReadFile(filepath.Join(dir, "recover_err.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := string(b)
+	for _, want := range []string{`"panic":"worker crashed"`, `"panic":"again"`, `"panic":"handler crashed"`, `"diy":"d"`, `recover_test.go`, `"stack":"goroutine `} {
+		if !strings.Contains(out, want) {
+			t.Errorf("%s should be in %s", want, out)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package golog
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	"go.uber.org/multierr"
+)
+
+// Reopen close all the files in use, the next write open them by name again,
+// call it after the files are moved by logrotate, it is safe while logging
+func Reopen() error {
+	return _log.Reopen()
+}
+
+func (l *logger) Reopen() error {
+	l.coreMu.RLock()
+	defer l.coreMu.RUnlock()
+
+	var err error
+	for _, closer := range l.closers {
+		if w, ok := closer.(interface{ Reopen() error }); ok {
+			err = multierr.Append(err, w.Reopen())
+		}
+	}
+	return err
+}
+
+// ReopenOnSIGHUP call l.Reopen when the process receive SIGHUP, eg: sent by the postrotate of logrotate,
+// call stop to stop handling the signal
+func ReopenOnSIGHUP(l LoggerInterface) (stop func()) {
+	c := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(c, syscall.SIGHUP)
+
+	go func() {
+		for {
+			select {
+			case <-c:
+				if err := l.Reopen(); err != nil {
+					fmt.Fprintf(os.Stderr, "golog: reopen: %s\n", err)
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(c)
+			close(done)
+		})
+	}
+}
@@ -0,0 +1,76 @@
+//go:build !windows
+
+package golog
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestReopen(t *testing.T) {
+	dir := t.TempDir()
+	l := New().SetOutputFile(dir, "reopen")
+	l.InitLogger()
+
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+				l.Info("concurrent")
+			}
+		}
+	}()
+
+	l.Info("before move")
+	link := filepath.Join(dir, "reopen_info.log")
+	current, err := filepath.EvalSymlinks(link)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// same as logrotate move the file then send SIGHUP
+	if err := os.Rename(current, current+".moved"); err != nil {
+		t.Fatal(err)
+	}
+
+	stopSignal := ReopenOnSIGHUP(l)
+	defer stopSignal()
+	if err := syscall.Kill(os.Getpid(), syscall.SIGHUP); err != nil {
+		t.Fatal(err)
+	}
+
+	var b []byte
+	for deadline := time.Now().Add(2 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		l.Info("after move")
+		if b, err = os.ReadFile(current); err == nil && strings.Contains(string(b), "after move") {
+			break
+		}
+	}
+
+	close(stop)
+	wg.Wait()
+
+	if !strings.Contains(string(b), "after move") {
+		t.Fatalf("the file should be created again, got %q %v", b, err)
+	}
+
+	if err := l.Reopen(); err != nil {
+		t.Fatal(err)
+	}
+
+	moved, err := os.ReadFile(current + ".moved")
+	if err != nil || !strings.Contains(string(moved), "before move") {
+		t.Fatalf("the moved file should keep the old lines, got %q %v", moved, err)
+	}
+}
@@ -0,0 +1,313 @@
+package golog
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	rotateLogs "github.com/lestrrat-go/file-rotatelogs"
+)
+
+// fileWriter is the rotatelogs can be reopened, eg: after the file moved by logrotate
+type fileWriter struct {
+	pattern string
+	opts    []rotateLogs.Option
+
+	// mu writing hold the read lock, Reopen hold the write lock to swap in the new hook
+	mu   sync.RWMutex
+	hook *rotateLogs.RotateLogs
+	// reopened the file in use before Reopen, it is still in use until the next write
+	reopened string
+}
+
+func newFileWriter(pattern string, opts ...rotateLogs.Option) (*fileWriter, error) {
+	hook, err := rotateLogs.New(pattern, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &fileWriter{pattern: pattern, opts: opts, hook: hook}, nil
+}
+
+func (w *fileWriter) Write(p []byte) (int, error) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	return w.hook.Write(p)
+}
+
+// Reopen close the file in use, the next write open the file by name again,
+// create it if it is moved
+func (w *fileWriter) Reopen() error {
+	hook, err := rotateLogs.New(w.pattern, w.opts...)
+	if err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	old := w.hook
+	w.hook = hook
+	if current := old.CurrentFileName(); current != "" {
+		w.reopened = current
+	}
+	w.mu.Unlock()
+
+	return old.Close()
+}
+
+func (w *fileWriter) Close() error {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	return w.hook.Close()
+}
+
+func (w *fileWriter) CurrentFileName() string {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	if current := w.hook.CurrentFileName(); current != "" {
+		return current
+	}
+	return w.reopened
+}
+
+// retention remove the rotated files of all the writers in the log path,
+// it runs after every rotation, the file in use and the link are never touched
+type retention struct {
+	maxAge     time.Duration
+	maxBackups int
+	// maxSize the total size of the log path, the oldest rotated files are removed first
+	maxSize int64
+	// compress the rotated files by gzip or zstd when not empty
+	compress string
+
+	mu      sync.Mutex
+	writers []*retentionWriter
+}
+
+type retentionWriter struct {
+	linkName string
+	file     *fileWriter
+}
+
+type backupFile struct {
+	path    string
+	size    int64
+	modTime time.Time
+}
+
+func (r *retention) add(linkName string, file *fileWriter) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.writers = append(r.writers, &retentionWriter{linkName: linkName, file: file})
+}
+
+// Handle implement rotateLogs.Handler, called in a new goroutine after the file rotated
+func (r *retention) Handle(e rotateLogs.Event) {
+	if e.Type() == rotateLogs.FileRotatedEventType {
+		r.sweep()
+	}
+}
+
+func (r *retention) sweep() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var kept []backupFile
+	var total int64
+	for _, w := range r.writers {
+		files, current := backups(w.linkName, w.file)
+		if fi, err := os.Stat(current); err == nil {
+			total += fi.Size()
+		}
+
+		for i, b := range files {
+			if (r.maxBackups > 0 && i >= r.maxBackups) || (r.maxAge > 0 && time.Since(b.modTime) > r.maxAge) {
+				_ = os.Remove(b.path)
+				continue
+			}
+
+			if r.compress != "" && !isCompressed(b.path) {
+				if err := compressFile(&b, r.compress); err != nil {
+					fmt.Fprintf(os.Stderr, "golog: compress %s: %s\n", b.path, err)
+				}
+			}
+
+			kept = append(kept, b)
+			total += b.size
+		}
+	}
+
+	if r.maxSize <= 0 || total <= r.maxSize {
+		return
+	}
+
+	sort.Slice(kept, func(i, j int) bool {
+		return kept[i].modTime.Before(kept[j].modTime)
+	})
+
+	for _, b := range kept {
+		if total <= r.maxSize {
+			return
+		}
+
+		if err := os.Remove(b.path); err == nil {
+			total -= b.size
+		}
+	}
+}
+
+// backups list the rotated files of the link name, newest first, and the file in use,
+// which is read after listing so a file rotated out meanwhile is closed already
+func backups(linkName string, file *fileWriter) ([]backupFile, string) {
+	matches, err := filepath.Glob(linkName + ".*")
+	current := file.CurrentFileName()
+	if err != nil {
+		return nil, current
+	}
+
+	files := make([]backupFile, 0, len(matches))
+	for _, path := range matches {
+		if path == current || strings.HasSuffix(path, "_lock") || strings.HasSuffix(path, "_symlink") ||
+			strings.HasSuffix(path, compressTmpExt) {
+			continue
+		}
+
+		fi, err := os.Lstat(path)
+		if err != nil || !fi.Mode().IsRegular() {
+			continue
+		}
+
+		files = append(files, backupFile{path: path, size: fi.Size(), modTime: fi.ModTime()})
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].modTime.After(files[j].modTime)
+	})
+	return files, current
+}
+
+const (
+	// CompressGzip compress the rotated files to .gz, other compress can be added by RegisterCompressor,
+	// eg: zstd by importing github.com/hunterhug/golog/zstd
+	CompressGzip = "gzip"
+
+	compressTmpExt = ".tmp"
+)
+
+// Compressor return the writer compress to w, the rotated file is copied to it then closed
+type Compressor func(w io.Writer) (io.WriteCloser, error)
+
+type compressor struct {
+	ext string
+	new Compressor
+}
+
+var (
+	compressorsMu sync.RWMutex
+	compressors   = map[string]compressor{
+		CompressGzip: {ext: ".gz", new: func(w io.Writer) (io.WriteCloser, error) {
+			return gzip.NewWriter(w), nil
+		}},
+	}
+)
+
+// RegisterCompressor add the compress can be used by SetFileCompress, the rotated files get the ext, eg: ".zst",
+// usually called in init
+func RegisterCompressor(name, ext string, c Compressor) {
+	compressorsMu.Lock()
+	defer compressorsMu.Unlock()
+
+	compressors[name] = compressor{ext: ext, new: c}
+}
+
+func getCompressor(name string) (compressor, bool) {
+	compressorsMu.RLock()
+	defer compressorsMu.RUnlock()
+
+	c, ok := compressors[name]
+	return c, ok
+}
+
+func isCompressed(path string) bool {
+	compressorsMu.RLock()
+	defer compressorsMu.RUnlock()
+
+	for _, c := range compressors {
+		if strings.HasSuffix(path, c.ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// compressFile write b to a temp file then rename it, remove b at last, keep the mod time for the sweep
+func compressFile(b *backupFile, compress string) error {
+	c, ok := getCompressor(compress)
+	if !ok {
+		return fmt.Errorf("unknown compress %q", compress)
+	}
+
+	src, err := os.Open(b.path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	name := b.path + c.ext
+	tmp := name + compressTmpExt
+	dst, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+
+	if err := compressTo(dst, src, c.new); err != nil {
+		dst.Close()
+		os.Remove(tmp)
+		return err
+	}
+
+	if err := dst.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+
+	if err := os.Chtimes(tmp, b.modTime, b.modTime); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+
+	if err := os.Rename(tmp, name); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+
+	if fi, err := os.Stat(name); err == nil {
+		b.size = fi.Size()
+	}
+
+	old := b.path
+	b.path = name
+	return os.Remove(old)
+}
+
+func compressTo(dst io.Writer, src io.Reader, newWriter Compressor) error {
+	w, err := newWriter(dst)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(w, src); err != nil {
+		w.Close()
+		return err
+	}
+	return w.Close()
+}
@@ -0,0 +1,117 @@
+package golog
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRotateSize(t *testing.T) {
+	dir := t.TempDir()
+	l := New().SetOutputFile(dir, "size").SetFileMaxSize(200).SetFileMaxBackups(2)
+	l.InitLogger()
+
+	for i := 0; i < 20; i++ {
+		l.Info(strings.Repeat("x", 100))
+	}
+
+	// the sweep run in background after rotated
+	var rotated []string
+	for deadline := time.Now().Add(2 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		rotated, _ = filepath.Glob(filepath.Join(dir, "size_info.log.*"))
+		if len(rotated) == 3 {
+			break
+		}
+	}
+
+	if len(rotated) != 3 {
+		t.Fatalf("want the file in use and 2 backups, got %v", rotated)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "size_info.log")); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRetentionMaxSize(t *testing.T) {
+	dir := t.TempDir()
+	link := filepath.Join(dir, "a.log")
+	old := time.Now().Add(-time.Hour)
+	for i, name := range []string{"a.log.1", "a.log.2", "a.log.3"} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, make([]byte, 100), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		mod := old.Add(time.Duration(i) * time.Minute)
+		if err := os.Chtimes(path, mod, mod); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	hook, err := newFileWriter(link + ".%Y%m%d.log")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := &retention{maxSize: 250}
+	r.add(link, hook)
+	r.sweep()
+
+	for name, want := range map[string]bool{"a.log.1": false, "a.log.2": true, "a.log.3": true} {
+		if _, err := os.Stat(filepath.Join(dir, name)); (err == nil) != want {
+			t.Errorf("%s exist should be %v", name, want)
+		}
+	}
+}
+
+func TestRotateCompress(t *testing.T) {
+	dir := t.TempDir()
+	l := New().SetOutputFile(dir, "zip").SetFileMaxSize(300).SetFileCompress(CompressGzip)
+	l.InitLogger()
+
+	for i := 0; i < 5; i++ {
+		l.Error(strings.Repeat("x", 100))
+	}
+
+	var zipped []string
+	for deadline := time.Now().Add(2 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		zipped, _ = filepath.Glob(filepath.Join(dir, "zip_err.log.*.gz"))
+		if len(zipped) == 2 {
+			break
+		}
+	}
+
+	if len(zipped) != 2 {
+		t.Fatalf("want 2 rotated files compressed, got %v", zipped)
+	}
+
+	f, err := os.Open(zipped[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	r, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := io.ReadAll(r)
+	if err != nil || !strings.Contains(string(b), strings.Repeat("x", 100)) {
+		t.Errorf("bad compressed file %q %v", b, err)
+	}
+
+	// the link still point to the file in use
+	if b, err := os.ReadFile(filepath.Join(dir, "zip_err.log")); err != nil || !strings.Contains(string(b), "x") {
+		t.Errorf("bad link %q %v", b, err)
+	}
+
+	if err := New().SetFileCompress("zstd").InitLoggerE(); err == nil {
+		t.Error("the compress not registered should fail")
+	}
+}
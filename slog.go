@@ -0,0 +1,259 @@
+package golog
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// NewSlogHandler return a slog.Handler write to l, so the log/slog output go to the cores l configured,
+// the field funcs of l run with the context passed to slog
+func NewSlogHandler(l LoggerInterface) slog.Handler {
+	return &slogHandler{l: l}
+}
+
+type slogHandler struct {
+	l LoggerInterface
+	// attrs added by WithAttrs, the key already prefixed by groups
+	attrs []Field
+	// prefix is the groups joined by dot
+	prefix string
+}
+
+func slogToLevel(level slog.Level) Level {
+	switch {
+	case level < slog.LevelInfo:
+		return DebugLevel
+	case level < slog.LevelWarn:
+		return InfoLevel
+	case level < slog.LevelError:
+		return WarnLevel
+	default:
+		return ErrorLevel
+	}
+}
+
+func levelToSlog(level Level) slog.Level {
+	switch {
+	case level <= DebugLevel:
+		return slog.LevelDebug
+	case level == InfoLevel:
+		return slog.LevelInfo
+	case level == WarnLevel:
+		return slog.LevelWarn
+	default:
+		return slog.LevelError
+	}
+}
+
+func (h *slogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	if level < slog.LevelInfo && IsForceDebug(ctx) {
+		return true
+	}
+
+	if l, ok := h.l.(*logger); ok {
+		l.coreMu.RLock()
+		defer l.coreMu.RUnlock()
+
+		z, _ := l.current()
+		return z.Core().Enabled(slogToLevel(level))
+	}
+
+	return h.l.GetLevel().Enabled(slogToLevel(level))
+}
+
+func (h *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	c := &slogHandler{l: h.l, prefix: h.prefix}
+	c.attrs = append(c.attrs, h.attrs...)
+	for _, a := range attrs {
+		c.attrs = appendAttr(c.attrs, h.prefix, a)
+	}
+	return c
+}
+
+func (h *slogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	return &slogHandler{l: h.l, attrs: h.attrs, prefix: h.prefix + name + "."}
+}
+
+func (h *slogHandler) Handle(ctx context.Context, r slog.Record) error {
+	fields := make([]Field, 0, len(h.attrs)+r.NumAttrs())
+	fields = append(fields, h.attrs...)
+	r.Attrs(func(a slog.Attr) bool {
+		fields = appendAttr(fields, h.prefix, a)
+		return true
+	})
+
+	level := slogToLevel(r.Level)
+	l, ok := h.l.(*logger)
+	if !ok {
+		m := make(map[string]interface{}, len(fields))
+		enc := zapcore.NewMapObjectEncoder()
+		for _, f := range fields {
+			f.AddTo(enc)
+		}
+		for k, v := range enc.Fields {
+			m[k] = v
+		}
+
+		switch level {
+		case DebugLevel:
+			h.l.DebugContextWithFields(ctx, m, r.Message)
+		case InfoLevel:
+			h.l.InfoContextWithFields(ctx, m, r.Message)
+		case WarnLevel:
+			h.l.WarnContextWithFields(ctx, m, r.Message)
+		default:
+			h.l.ErrorContextWithFields(ctx, m, r.Message)
+		}
+		return nil
+	}
+
+	m := l.addField(ctx, nil)
+	kvs := with(m)
+	for i := 0; i < len(kvs); i += 2 {
+		fields = append(fields, zap.Any(kvs[i].(string), kvs[i+1]))
+	}
+
+	l.coreMu.RLock()
+	defer l.coreMu.RUnlock()
+
+	// the caller come from the record, not from the zap logger
+	z, _ := l.current()
+	core := z.Core()
+	if level == DebugLevel && IsForceDebug(ctx) {
+		core = forceCore(core)
+	}
+
+	ent := zapcore.Entry{
+		LoggerName: l.GetName(),
+		Time:       r.Time,
+		Level:      level,
+		Message:    r.Message,
+	}
+	if ent.Time.IsZero() {
+		ent.Time = time.Now()
+	}
+
+	if r.PC != 0 {
+		frame, _ := runtime.CallersFrames([]uintptr{r.PC}).Next()
+		ent.Caller = zapcore.EntryCaller{
+			Defined:  true,
+			PC:       r.PC,
+			File:     frame.File,
+			Line:     frame.Line,
+			Function: frame.Function,
+		}
+	}
+
+	if ce := core.Check(ent, nil); ce != nil {
+		ce.Write(fields...)
+	}
+	return nil
+}
+
+// appendAttr flatten the group to dot separated key
+func appendAttr(fields []Field, prefix string, a slog.Attr) []Field {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return fields
+	}
+
+	if a.Value.Kind() == slog.KindGroup {
+		groupPrefix := prefix
+		if a.Key != "" {
+			groupPrefix = prefix + a.Key + "."
+		}
+
+		for _, ga := range a.Value.Group() {
+			fields = appendAttr(fields, groupPrefix, ga)
+		}
+		return fields
+	}
+
+	return append(fields, zap.Any(prefix+a.Key, a.Value.Any()))
+}
+
+// NewFromSlog return a LoggerInterface write all entries to h, the level is debug by default
+// so h decide what to write, SetOutputFile and SetIsOutputStdout take no effect on it
+func NewFromSlog(h slog.Handler) LoggerInterface {
+	l := New().(*logger)
+	l.slogHandler = h
+	l.SetLevel(DebugLevel)
+	l.InitLogger()
+	return l
+}
+
+// slogCore is the zap core write to a slog.Handler
+type slogCore struct {
+	h slog.Handler
+}
+
+func newSlogCore(h slog.Handler) zapcore.Core {
+	return &slogCore{h: h}
+}
+
+func (c *slogCore) Enabled(lvl zapcore.Level) bool {
+	return c.h.Enabled(context.Background(), levelToSlog(lvl))
+}
+
+func (c *slogCore) With(fields []zapcore.Field) zapcore.Core {
+	return &slogCore{h: c.h.WithAttrs(fieldsToAttrs(fields))}
+}
+
+func (c *slogCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *slogCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	var pc uintptr
+	if ent.Caller.Defined {
+		pc = ent.Caller.PC
+	}
+
+	r := slog.NewRecord(ent.Time, levelToSlog(ent.Level), ent.Message, pc)
+	if ent.LoggerName != "" {
+		r.AddAttrs(slog.String("logger", ent.LoggerName))
+	}
+	r.AddAttrs(fieldsToAttrs(fields)...)
+	return c.h.Handle(context.Background(), r)
+}
+
+func (c *slogCore) Sync() error {
+	return nil
+}
+
+// fieldsToAttrs keep the order of the fields, inline fields come last
+func fieldsToAttrs(fields []zapcore.Field) []slog.Attr {
+	enc := zapcore.NewMapObjectEncoder()
+	for _, f := range fields {
+		f.AddTo(enc)
+	}
+
+	attrs := make([]slog.Attr, 0, len(enc.Fields))
+	done := make(map[string]bool, len(enc.Fields))
+	for _, f := range fields {
+		if v, ok := enc.Fields[f.Key]; ok && !done[f.Key] {
+			attrs = append(attrs, slog.Any(f.Key, v))
+			done[f.Key] = true
+		}
+	}
+
+	kvs := with(enc.Fields)
+	for i := 0; i < len(kvs); i += 2 {
+		if key := kvs[i].(string); !done[key] {
+			attrs = append(attrs, slog.Any(key, kvs[i+1]))
+		}
+	}
+	return attrs
+}
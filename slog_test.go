@@ -0,0 +1,61 @@
+package golog
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSlogHandler(t *testing.T) {
+	dir := t.TempDir()
+	l := New().SetOutputFile(dir, "slog").SetOutputJson(true).SetCallerShort(true)
+	l.SetFieldFunc("diy", func(ctx context.Context, m map[string]interface{}) {
+		m["diy"] = ctx.Value("diy")
+	})
+	l.InitLogger()
+
+	ctx := context.WithValue(context.Background(), "diy", "d")
+	s := slog.New(NewSlogHandler(l)).With("a", 1).WithGroup("g")
+	s.InfoContext(ctx, "hello", "b", 2)
+	s.Debug("should not output")
+
+	b, err := ioutil.ReadFile(filepath.Join(dir, "slog_info.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := string(b)
+	for _, want := range []string{`"msg":"hello"`, `"a":1`, `"g.b":2`, `"diy":"d"`, `slog_test.go`} {
+		if !strings.Contains(out, want) {
+			t.Errorf("%s should be in %s", want, out)
+		}
+	}
+
+	if strings.Contains(out, "should not output") {
+		t.Errorf("debug should not output: %s", out)
+	}
+}
+
+func TestNewFromSlog(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewFromSlog(slog.NewJSONHandler(&buf, nil)).Named("sub")
+
+	l.With(map[string]interface{}{"k": "v"}).InfoF("typed", Int("n", 1))
+	l.Debug("should not output")
+	l.Warnf("warn %d", 2)
+
+	out := buf.String()
+	for _, want := range []string{`"msg":"typed"`, `"logger":"sub"`, `"k":"v"`, `"n":1`, `"level":"WARN","msg":"warn 2"`} {
+		if !strings.Contains(out, want) {
+			t.Errorf("%s should be in %s", want, out)
+		}
+	}
+
+	if strings.Contains(out, "should not output") {
+		t.Errorf("debug should not output: %s", out)
+	}
+}
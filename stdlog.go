@@ -0,0 +1,85 @@
+package golog
+
+import (
+	"bytes"
+	"log"
+	"sync/atomic"
+)
+
+// stdLogDepth is the frames between the caller and the Write: log.Printf and log.(*Logger).output
+const stdLogDepth = 2
+
+// stdLogWriter write every line of the standard library log to the logger at level
+type stdLogWriter struct {
+	l     LoggerInterface
+	level Level
+	// child is the *logger with the caller skip fixed for the standard library log
+	child atomic.Value
+}
+
+type stdLogChild struct {
+	skip int
+	l    *logger
+}
+
+func (w *stdLogWriter) Write(p []byte) (int, error) {
+	msg := string(bytes.TrimSuffix(p, []byte("\n")))
+
+	l, ok := w.l.(*logger)
+	if !ok {
+		switch w.level {
+		case DebugLevel:
+			w.l.Debug(msg)
+		case InfoLevel:
+			w.l.Info(msg)
+		case WarnLevel:
+			w.l.Warn(msg)
+		default:
+			w.l.Error(msg)
+		}
+		return len(p), nil
+	}
+
+	// the zap logger skip the layers set by SetCallerSkip, replace them by the Write and the std log frames
+	skip := l.GetCallerSkip()
+	if skip <= 0 {
+		skip = 1
+	}
+
+	c, ok := w.child.Load().(*stdLogChild)
+	if !ok || c.skip != skip {
+		c = &stdLogChild{skip: skip, l: l.child("", nil, 1+stdLogDepth-skip)}
+		w.child.Store(c)
+	}
+
+	c.l.logF(w.level, msg, nil)
+	return len(p), nil
+}
+
+// NewStdLog return a standard library *log.Logger write to l at level
+func NewStdLog(l LoggerInterface, level Level) *log.Logger {
+	return log.New(&stdLogWriter{l: l, level: level}, "", 0)
+}
+
+// NewStdLogAt return a standard library *log.Logger write to the default logger at level
+func NewStdLogAt(level Level) *log.Logger {
+	return NewStdLog(_log, level)
+}
+
+// RedirectStdLog make the output of the standard library log package go to l at level,
+// call restore to put back the previous output, flags and prefix
+func RedirectStdLog(l LoggerInterface, level Level) (restore func()) {
+	flags := log.Flags()
+	prefix := log.Prefix()
+	out := log.Writer()
+
+	log.SetFlags(0)
+	log.SetPrefix("")
+	log.SetOutput(&stdLogWriter{l: l, level: level})
+
+	return func() {
+		log.SetFlags(flags)
+		log.SetPrefix(prefix)
+		log.SetOutput(out)
+	}
+}
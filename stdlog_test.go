@@ -0,0 +1,35 @@
+package golog
+
+import (
+	"io/ioutil"
+	"log"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRedirectStdLog(t *testing.T) {
+	dir := t.TempDir()
+	l := New().SetOutputFile(dir, "std").SetOutputJson(true).SetCallerShort(true)
+	l.InitLogger()
+
+	restore := RedirectStdLog(l, WarnLevel)
+	fromStd := nextCaller()
+	log.Printf("from std %d", 1)
+	restore()
+
+	fromLogger := nextCaller()
+	NewStdLog(l, ErrorLevel).Println("from logger")
+
+	b, err := ioutil.ReadFile(filepath.Join(dir, "std_warn.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := string(b)
+	for _, want := range []string{`"l":"warn"`, fromStd + `,"msg":"from std 1"`, `"l":"error"`, fromLogger + `,"msg":"from logger"`} {
+		if !strings.Contains(out, want) {
+			t.Errorf("%s should be in %s", want, out)
+		}
+	}
+}
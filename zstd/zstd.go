@@ -0,0 +1,22 @@
+// Package zstd register the zstd compress of the rotated files, it is faster and smaller than gzip, use it as:
+//
+//	import "github.com/hunterhug/golog/zstd"
+//
+//	golog.SetFileCompress(zstd.Compress)
+package zstd
+
+import (
+	"io"
+
+	"github.com/hunterhug/golog"
+	klauspostZstd "github.com/klauspost/compress/zstd"
+)
+
+// Compress compress the rotated files to .zst
+const Compress = "zstd"
+
+func init() {
+	golog.RegisterCompressor(Compress, ".zst", func(w io.Writer) (io.WriteCloser, error) {
+		return klauspostZstd.NewWriter(w)
+	})
+}
@@ -0,0 +1,52 @@
+package zstd
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hunterhug/golog"
+	klauspostZstd "github.com/klauspost/compress/zstd"
+)
+
+func TestCompress(t *testing.T) {
+	dir := t.TempDir()
+	l := golog.New().SetOutputFile(dir, "zip").SetFileMaxSize(300).SetFileCompress(Compress)
+	l.InitLogger()
+
+	for i := 0; i < 5; i++ {
+		l.Error(strings.Repeat("x", 100))
+	}
+
+	var zipped []string
+	for deadline := time.Now().Add(2 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		zipped, _ = filepath.Glob(filepath.Join(dir, "zip_err.log.*.zst"))
+		if len(zipped) == 2 {
+			break
+		}
+	}
+
+	if len(zipped) != 2 {
+		t.Fatalf("want 2 rotated files compressed, got %v", zipped)
+	}
+
+	f, err := os.Open(zipped[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	r, err := klauspostZstd.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	b, err := io.ReadAll(r)
+	if err != nil || !strings.Contains(string(b), strings.Repeat("x", 100)) {
+		t.Errorf("bad compressed file %q %v", b, err)
+	}
+}